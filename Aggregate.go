@@ -0,0 +1,217 @@
+package PointerList
+
+import "container/heap"
+
+/////////////////////////////////
+//          Aggregate          //
+/////////////////////////////////
+
+//Returns the smallest element of the PointerList[T] using the specified LessPointerFunc[T]. Returns nil if the list is empty.
+func MinBy[T any](l PointerList[T], less LessPointerFunc[T]) *T {
+	return minBy(snapshotOf[T](l), less)
+}
+
+//Returns the largest element of the PointerList[T] using the specified LessPointerFunc[T]. Returns nil if the list is empty.
+func MaxBy[T any](l PointerList[T], less LessPointerFunc[T]) *T {
+	return maxBy(snapshotOf[T](l), less)
+}
+
+//Computes the sum of the values selected from every element of the PointerList[T].
+func SumBy[T any, N Number](l PointerList[T], f NumberPointerFunc[T, N]) N {
+	return sumBy(snapshotOf[T](l), f)
+}
+
+//Computes the average of the values selected from every element of the PointerList[T]. Returns 0 if the list is empty.
+func AverageBy[T any, N Number](l PointerList[T], f NumberPointerFunc[T, N]) float64 {
+	return averageBy(snapshotOf[T](l), f)
+}
+
+//Returns the k largest elements of the PointerList[T], largest first, without sorting the entire list.
+func TopK[T any](l PointerList[T], k int, less LessPointerFunc[T]) []*T {
+	return topK(snapshotOf[T](l), k, less)
+}
+
+//Counts the elements of the PointerList[T] per bucket.
+func Histogram[T any](l PointerList[T], f KeyPointerFunc[T, string]) map[string]int {
+	return histogram(snapshotOf[T](l), f)
+}
+
+//Returns the smallest element of every tag.
+func MapMinBy[T any](l TagSource[T], less LessPointerFunc[T]) map[string]*T {
+	retMap := make(map[string]*T)
+
+	for key, items := range l.ToArrayMap() {
+		retMap[key] = minBy(items, less)
+	}
+
+	return retMap
+}
+
+//Returns the largest element of every tag.
+func MapMaxBy[T any](l TagSource[T], less LessPointerFunc[T]) map[string]*T {
+	retMap := make(map[string]*T)
+
+	for key, items := range l.ToArrayMap() {
+		retMap[key] = maxBy(items, less)
+	}
+
+	return retMap
+}
+
+//Computes the sum of the selected values of every tag.
+func MapSumBy[T any, N Number](l TagSource[T], f NumberPointerFunc[T, N]) map[string]N {
+	retMap := make(map[string]N)
+
+	for key, items := range l.ToArrayMap() {
+		retMap[key] = sumBy(items, f)
+	}
+
+	return retMap
+}
+
+//Computes the average of the selected values of every tag.
+func MapAverageBy[T any, N Number](l TagSource[T], f NumberPointerFunc[T, N]) map[string]float64 {
+	retMap := make(map[string]float64)
+
+	for key, items := range l.ToArrayMap() {
+		retMap[key] = averageBy(items, f)
+	}
+
+	return retMap
+}
+
+//Returns the k largest elements of every tag, largest first.
+func MapTopK[T any](l TagSource[T], k int, less LessPointerFunc[T]) map[string][]*T {
+	retMap := make(map[string][]*T)
+
+	for key, items := range l.ToArrayMap() {
+		retMap[key] = topK(items, k, less)
+	}
+
+	return retMap
+}
+
+//Counts the elements of every tag per bucket.
+func MapHistogram[T any](l TagSource[T], f KeyPointerFunc[T, string]) map[string]map[string]int {
+	retMap := make(map[string]map[string]int)
+
+	for key, items := range l.ToArrayMap() {
+		retMap[key] = histogram(items, f)
+	}
+
+	return retMap
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func minBy[T any](items []*T, less LessPointerFunc[T]) *T {
+	//A nil element can be selected, so the index marks that nothing is selected yet.
+	selected := -1
+
+	for i := 0; i < len(items); i++ {
+		if selected < 0 || less(items[i], items[selected]) {
+			selected = i
+		}
+	}
+
+	if selected < 0 {
+		return nil
+	}
+
+	return items[selected]
+}
+
+func maxBy[T any](items []*T, less LessPointerFunc[T]) *T {
+	//A nil element can be selected, so the index marks that nothing is selected yet.
+	selected := -1
+
+	for i := 0; i < len(items); i++ {
+		if selected < 0 || less(items[selected], items[i]) {
+			selected = i
+		}
+	}
+
+	if selected < 0 {
+		return nil
+	}
+
+	return items[selected]
+}
+
+func sumBy[T any, N Number](items []*T, f NumberPointerFunc[T, N]) N {
+	var sum N
+
+	for i := 0; i < len(items); i++ {
+		sum += f(items[i])
+	}
+
+	return sum
+}
+
+func averageBy[T any, N Number](items []*T, f NumberPointerFunc[T, N]) float64 {
+	if len(items) == 0 {
+		return 0
+	}
+
+	var sum float64
+
+	for i := 0; i < len(items); i++ {
+		sum += float64(f(items[i]))
+	}
+
+	return sum / float64(len(items))
+}
+
+func topK[T any](items []*T, k int, less LessPointerFunc[T]) []*T {
+	if k <= 0 {
+		return []*T{}
+	}
+
+	//Min-heap of the k largest elements seen so far, the smallest of them is at the root.
+	h := &topKHeap[T]{less: less}
+
+	for i := 0; i < len(items); i++ {
+		if len(h.items) < k {
+			heap.Push(h, items[i])
+		} else if less(h.items[0], items[i]) {
+			h.items[0] = items[i]
+			heap.Fix(h, 0)
+		}
+	}
+
+	retList := make([]*T, len(h.items))
+
+	for i := len(retList) - 1; i >= 0; i-- {
+		retList[i] = heap.Pop(h).(*T)
+	}
+
+	return retList
+}
+
+func histogram[T any](items []*T, f KeyPointerFunc[T, string]) map[string]int {
+	count := make(map[string]int)
+
+	for i := 0; i < len(items); i++ {
+		count[f(items[i])]++
+	}
+
+	return count
+}
+
+type topKHeap[T any] struct {
+	items []*T
+	less  LessPointerFunc[T]
+}
+
+func (h *topKHeap[T]) Len() int           { return len(h.items) }
+func (h *topKHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *topKHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *topKHeap[T]) Push(x any)         { h.items = append(h.items, x.(*T)) }
+
+func (h *topKHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
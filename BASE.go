@@ -1,6 +1,9 @@
 package PointerList
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
 type BASE interface {
 	start() // if BaseList != nil => start()
@@ -10,12 +13,149 @@ type BASE interface {
 type lockerBase struct {
 	BASE
 	locker sync.Mutex
+	hook   baseHook
 }
 
 func (l *lockerBase) start() {
 	l.locker.Lock()
+
+	if l.hook != nil {
+		l.hook.started()
+	}
 }
 
 func (l *lockerBase) end() {
+	var after func()
+	if l.hook != nil {
+		after = l.hook.released()
+	}
+
 	l.locker.Unlock()
+
+	if after != nil {
+		after()
+	}
+}
+
+func (l *lockerBase) tryStart(timeout time.Duration) bool {
+	if !tryLock(&l.locker, timeout) {
+		return false
+	}
+
+	if l.hook != nil {
+		l.hook.started()
+	}
+
+	return true
+}
+
+func (l *lockerBase) setHook(hook baseHook) {
+	l.hook = hook
+}
+
+//BASE of an unguarded list that needs a hook.
+type hookBase struct {
+	BASE
+	hook baseHook
+}
+
+func (l *hookBase) start() {
+	l.hook.started()
+}
+
+func (l *hookBase) end() {
+	if after := l.hook.released(); after != nil {
+		after()
+	}
+}
+
+func (l *hookBase) setHook(hook baseHook) {
+	l.hook = hook
+}
+
+//Called by a BASE, for example to drop expired elements before the list is used.
+type baseHook interface {
+	//Called after the BASE was taken.
+	started()
+	//Called before the BASE is released. The returned func, if any, is called after it was released.
+	released() func()
+}
+
+//Every hook of a list, called in the order they were installed.
+type baseHooks []baseHook
+
+func (h baseHooks) started() {
+	for _, hook := range h {
+		hook.started()
+	}
+}
+
+func (h baseHooks) released() func() {
+	var afters []func()
+
+	for _, hook := range h {
+		if after := hook.released(); after != nil {
+			afters = append(afters, after)
+		}
+	}
+
+	if len(afters) == 0 {
+		return nil
+	}
+
+	return func() {
+		for _, after := range afters {
+			after()
+		}
+	}
+}
+
+//Optional for a BASE, used to install a baseHook. The hook is set while the BASE is taken.
+type hookedBASE interface {
+	setHook(hook baseHook)
+}
+
+//Optional for a BASE, used by the methods that only read the list. A BASE without it is taken with start and end.
+type readBASE interface {
+	startRead()
+	endRead()
+}
+
+//Optional for a BASE, used by the Try... methods. A BASE without it is waited for.
+type tryBASE interface {
+	tryStart(timeout time.Duration) bool
+}
+
+//Takes the BASE within timeout. A BASE without tryStart is waited for.
+func tryStartBase(base BASE, timeout time.Duration) bool {
+	if t, ok := base.(tryBASE); ok {
+		return t.tryStart(timeout)
+	}
+
+	base.start()
+	return true
+}
+
+//Tries to lock the mutex until timeout expires. A timeout <= 0 tries only once.
+func tryLock(locker *sync.Mutex, timeout time.Duration) bool {
+	if locker.TryLock() {
+		return true
+	}
+
+	deadline := time.Now().Add(timeout)
+	wait := 10 * time.Microsecond
+
+	for time.Now().Before(deadline) {
+		time.Sleep(wait)
+
+		if locker.TryLock() {
+			return true
+		}
+
+		if wait < time.Millisecond {
+			wait *= 2
+		}
+	}
+
+	return false
 }
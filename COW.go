@@ -0,0 +1,236 @@
+package PointerList
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/////////////////////////////////
+//     Copy-On-Write List      //
+/////////////////////////////////
+
+type cowList[T any] struct {
+	*pointerList[T]
+	base *cowBase[T]
+}
+
+//GuardedPointerList[T] for read heavy use. Get, Count, ToArray, Version, Find, FindAll, TrueForAll, TryFind and Foreach read
+//the last published array without locking. The other methods that only read, such as Contains, IndexOf, Lookup and the
+//package helpers, take the writer mutex briefly without copying. Every method that changes the list copies the array
+//under the writer mutex and publishes the copy, so it is O(n). The readers do not drop expired elements of AddWithTTL, the next writer or the janitor does.
+func NewCOWPointerList[T any](options ...ListOption[T]) GuardedPointerList[T] {
+	base := &cowBase[T]{}
+
+	l := newPointerList(make([]*T, 0), base, options)
+
+	base.owner = l
+	base.publish()
+
+	return &cowList[T]{
+		pointerList: l,
+		base:        base,
+	}
+}
+
+//Gets the element at the specified index.
+func (c *cowList[T]) Get(index int) *T {
+	list := c.base.load()
+
+	if index >= len(list) || index < 0 {
+		return nil
+	}
+
+	return list[index]
+}
+
+//Returns the number of elements in a sequence.
+func (c *cowList[T]) Count() int {
+	return len(c.base.load())
+}
+
+//Returns the published array, it must not be changed.
+func (c *cowList[T]) ToArray() []*T {
+	return c.base.load()
+}
+
+//Returns the version of the published array.
+func (c *cowList[T]) Version() uint64 {
+	return c.base.current().version
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate, and returns the first occurrence within the entire PointerList[T].
+func (c *cowList[T]) Find(f FindPointerFunc[T]) *T {
+	list := c.base.load()
+
+	if index := sliceFindIndex(list, f); index >= 0 {
+		return list[index]
+	}
+
+	return nil
+}
+
+//Retrieves all the elements that match the conditions defined by the specified predicate.
+func (c *cowList[T]) FindAll(f FindPointerFunc[T]) []*T {
+	return sliceFindAll(c.base.load(), f)
+}
+
+//Determines whether every element in the PointerList[T] matches the conditions defined by the specified predicate.
+func (c *cowList[T]) TrueForAll(f TrueForAllPointerFunc[T]) bool {
+	return sliceTrueForAll(c.base.load(), f)
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. The published array is
+//read without locking, so it never times out.
+func (c *cowList[T]) TryFind(f FindPointerFunc[T], timeout time.Duration) (*T, bool) {
+	return c.Find(f), true
+}
+
+//Loop
+func (c *cowList[T]) Foreach(f ForeachListFunc[T]) {
+	sliceForeach(c.base.load(), f)
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+func (c *cowList[T]) FindCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error) {
+	list := c.base.load()
+
+	for i := 0; i < len(list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return nil, err
+		}
+
+		if f(i, list[i]) {
+			return list[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+//Loop. Returns ctx.Err() if the context is done.
+func (c *cowList[T]) ForeachCtx(ctx context.Context, f ForeachListFunc[T]) error {
+	list := c.base.load()
+
+	for i := 0; i < len(list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return err
+		}
+
+		if !f(i, list[i]) {
+			break
+		}
+	}
+
+	return nil
+}
+
+//Removes the first occurrence of a specific object from the PointerList[T]. NoSafe, the change is published.
+func (c *cowList[T]) RemoveNoSafe(targetItem *T) bool {
+	c.base.detach()
+	defer c.base.publish()
+
+	return c.remove(targetItem)
+}
+
+//Removes the element at the specified index of the PointerList[T]. NoSafe, the change is published.
+func (c *cowList[T]) RemoveAtNoSafe(index int) bool {
+	c.base.detach()
+	defer c.base.publish()
+
+	return c.removeAt(index)
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//BASE of a copy-on-write list. start gives the owner a copy of the published array, end publishes it.
+type cowBase[T any] struct {
+	BASE
+	owner  *pointerList[T]
+	locker sync.Mutex
+	hook   baseHook
+	//Holds a *cowSnapshot[T].
+	published atomic.Value
+}
+
+//The array and the version are published together, so a reader never gets the version of another array.
+type cowSnapshot[T any] struct {
+	list    []*T
+	version uint64
+}
+
+func (b *cowBase[T]) start() {
+	b.locker.Lock()
+	b.detach()
+
+	if b.hook != nil {
+		b.hook.started()
+	}
+}
+
+func (b *cowBase[T]) end() {
+	var after func()
+	if b.hook != nil {
+		after = b.hook.released()
+	}
+
+	b.publish()
+	b.locker.Unlock()
+
+	if after != nil {
+		after()
+	}
+}
+
+func (b *cowBase[T]) tryStart(timeout time.Duration) bool {
+	if !tryLock(&b.locker, timeout) {
+		return false
+	}
+
+	b.detach()
+
+	if b.hook != nil {
+		b.hook.started()
+	}
+
+	return true
+}
+
+//Takes the writer mutex without copying the array or calling the hook, the owner has the published array until the next start.
+func (b *cowBase[T]) startRead() {
+	b.locker.Lock()
+}
+
+func (b *cowBase[T]) endRead() {
+	b.locker.Unlock()
+}
+
+func (b *cowBase[T]) setHook(hook baseHook) {
+	b.hook = hook
+}
+
+func (b *cowBase[T]) load() []*T {
+	return b.current().list
+}
+
+func (b *cowBase[T]) current() *cowSnapshot[T] {
+	return b.published.Load().(*cowSnapshot[T])
+}
+
+//Gives the owner a copy of the published array, so the readers never see a change in place.
+func (b *cowBase[T]) detach() {
+	published := b.load()
+
+	list := make([]*T, len(published))
+	copy(list, published)
+
+	b.owner.list = list
+}
+
+//Publishes the array of the owner. It is read when called, not when deferred.
+func (b *cowBase[T]) publish() {
+	b.published.Store(&cowSnapshot[T]{list: b.owner.list, version: b.owner.version})
+}
@@ -0,0 +1,416 @@
+package PointerList
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+/////////////////////////////////
+//             CSV             //
+/////////////////////////////////
+
+//Header of the leading column written by the tag lists.
+const csvTagHeader = "tag"
+
+//Column of a CSV export.
+type Column[T any] struct {
+	Header string
+	Value  func(current *T) string
+}
+
+//Writes the PointerList[T] as CSV with a header row. Without columns, the exported fields of T are used,
+//named by the `list:"name"` struct tag or the field name, `list:"-"` skips a field. Nil elements are written as empty cells.
+func (l *pointerList[T]) ExportCSV(w io.Writer, columns ...Column[T]) error {
+	return writeListCSV[T](w, ',', l, columns)
+}
+
+//Adds the rows of a CSV with a header row to the end of the PointerList[T]. Nothing is added if a row is rejected.
+//Without build, the columns are parsed into the fields of T the same way ExportCSV writes them.
+func (l *pointerList[T]) ImportCSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readListCSV[T](r, ',', l, build)
+}
+
+//Same as ExportCSV, separated by tabs.
+func (l *pointerList[T]) ExportTSV(w io.Writer, columns ...Column[T]) error {
+	return writeListCSV[T](w, '\t', l, columns)
+}
+
+//Same as ImportCSV, separated by tabs.
+func (l *pointerList[T]) ImportTSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readListCSV[T](r, '\t', l, build)
+}
+
+//Writes the TagList as CSV with a leading "tag" column, ordered by key.
+func (l *tagList[T]) ExportCSV(w io.Writer, columns ...Column[T]) error {
+	return writeTagCSV[T](w, ',', l, columns)
+}
+
+//Adds the rows of a CSV with a leading "tag" column to the TagList. The limits of the tags are checked before the
+//first row is added, so nothing is added if a row does not fit.
+func (l *tagList[T]) ImportCSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, ',', build, l.fits, l.Add)
+}
+
+//Same as ExportCSV, separated by tabs.
+func (l *tagList[T]) ExportTSV(w io.Writer, columns ...Column[T]) error {
+	return writeTagCSV[T](w, '\t', l, columns)
+}
+
+//Same as ImportCSV, separated by tabs.
+func (l *tagList[T]) ImportTSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, '\t', build, l.fits, l.Add)
+}
+
+//Writes the GuardedTagList as CSV with a leading "tag" column, ordered by key.
+func (l *guardedTagList[T]) ExportCSV(w io.Writer, columns ...Column[T]) error {
+	return writeTagCSV[T](w, ',', l, columns)
+}
+
+//Adds the rows of a CSV with a leading "tag" column to the GuardedTagList. The limits of the tags are checked before the
+//first row is added, so nothing is added if a row does not fit.
+func (l *guardedTagList[T]) ImportCSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, ',', build, l.fits, l.Add)
+}
+
+//Same as ExportCSV, separated by tabs.
+func (l *guardedTagList[T]) ExportTSV(w io.Writer, columns ...Column[T]) error {
+	return writeTagCSV[T](w, '\t', l, columns)
+}
+
+//Same as ImportCSV, separated by tabs.
+func (l *guardedTagList[T]) ImportTSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, '\t', build, l.fits, l.Add)
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func writeListCSV[T any](w io.Writer, comma rune, l PointerList[T], columns []Column[T]) error {
+	columns, err := csvColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	if err := writer.Write(csvHeader(columns, false)); err != nil {
+		return err
+	}
+
+	for _, item := range snapshotOf[T](l) {
+		if err := writer.Write(csvRow(columns, "", item, false)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func readListCSV[T any](r io.Reader, comma rune, l PointerList[T], build CSVBuildFunc[T]) error {
+	items := make([]*T, 0)
+
+	err := readCSV(r, comma, build, false, func(tag string, item *T) {
+		items = append(items, item)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	l.AddRange(items)
+
+	return nil
+}
+
+func writeTagCSV[T any](w io.Writer, comma rune, l TagSource[T], columns []Column[T]) error {
+	columns, err := csvColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	if err := writer.Write(csvHeader(columns, true)); err != nil {
+		return err
+	}
+
+	items := l.ToArrayMap()
+	keys := make([]string, 0, len(items))
+
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, item := range items[key] {
+			if err := writer.Write(csvRow(columns, key, item, true)); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+//Adds the rows once fits accepted the number of rows of every tag.
+func readTagCSV[T any](r io.Reader, comma rune, build CSVBuildFunc[T], fits func(counts map[string]int) error, add func(key string, value *T) (*T, error)) error {
+	type row struct {
+		tag  string
+		item *T
+	}
+
+	rows := make([]row, 0)
+	counts := make(map[string]int)
+
+	err := readCSV(r, comma, build, true, func(tag string, item *T) {
+		rows = append(rows, row{tag: tag, item: item})
+		counts[tag]++
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := fits(counts); err != nil {
+		return err
+	}
+
+	for _, current := range rows {
+		if _, err := add(current.tag, current.item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//Calls add for every row. With withTag, a leading "tag" column is passed as tag and is not part of the record.
+func readCSV[T any](r io.Reader, comma rune, build CSVBuildFunc[T], withTag bool, add func(tag string, item *T)) error {
+	if build == nil {
+		var err error
+
+		if build, err = csvReflectBuild[T](); err != nil {
+			return err
+		}
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	tagColumn := -1
+	if withTag && len(header) > 0 && header[0] == csvTagHeader {
+		tagColumn = 0
+	}
+
+	for line := 2; ; line++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		tag := ""
+		record := make(map[string]string, len(header))
+
+		for i := 0; i < len(header) && i < len(values); i++ {
+			if i == tagColumn {
+				tag = values[i]
+			} else {
+				record[header[i]] = values[i]
+			}
+		}
+
+		item, err := build(record)
+		if err != nil {
+			return GetErrorf(CSVRecord, line, err)
+		}
+
+		add(tag, item)
+	}
+}
+
+func csvHeader[T any](columns []Column[T], withTag bool) []string {
+	header := make([]string, 0, len(columns)+1)
+
+	if withTag {
+		header = append(header, csvTagHeader)
+	}
+
+	for _, column := range columns {
+		header = append(header, column.Header)
+	}
+
+	return header
+}
+
+func csvRow[T any](columns []Column[T], tag string, item *T, withTag bool) []string {
+	row := make([]string, 0, len(columns)+1)
+
+	if withTag {
+		row = append(row, tag)
+	}
+
+	for _, column := range columns {
+		if item == nil {
+			row = append(row, "")
+		} else {
+			row = append(row, column.Value(item))
+		}
+	}
+
+	return row
+}
+
+func csvColumns[T any](columns []Column[T]) ([]Column[T], error) {
+	if len(columns) > 0 {
+		return columns, nil
+	}
+
+	fields, err := csvFields[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	columns = make([]Column[T], len(fields))
+
+	for i, field := range fields {
+		index := field.index
+
+		columns[i] = Column[T]{
+			Header: field.name,
+			Value: func(current *T) string {
+				value := reflect.ValueOf(current).Elem().Field(index)
+
+				if value.Kind() == reflect.Pointer {
+					if value.IsNil() {
+						return ""
+					}
+					value = value.Elem()
+				}
+
+				return fmt.Sprint(value.Interface())
+			},
+		}
+	}
+
+	return columns, nil
+}
+
+func csvReflectBuild[T any]() (CSVBuildFunc[T], error) {
+	fields, err := csvFields[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(record map[string]string) (*T, error) {
+		item := new(T)
+		value := reflect.ValueOf(item).Elem()
+
+		for _, field := range fields {
+			text, ok := record[field.name]
+			if !ok || text == "" {
+				continue
+			}
+
+			if err := setCSVValue(value.Field(field.index), text); err != nil {
+				return nil, err
+			}
+		}
+
+		return item, nil
+	}, nil
+}
+
+type csvField struct {
+	name  string
+	index int
+}
+
+func csvFields[T any]() ([]csvField, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	if t.Kind() != reflect.Struct {
+		return nil, GetErrorf(CSVUnsupportedType, t)
+	}
+
+	fields := make([]csvField, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get("list")
+
+		if name == "-" {
+			continue
+		} else if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, csvField{name: name, index: i})
+	}
+
+	return fields, nil
+}
+
+func setCSVValue(value reflect.Value, text string) error {
+	if value.Kind() == reflect.Pointer {
+		value.Set(reflect.New(value.Type().Elem()))
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(text, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		parsed, err := strconv.ParseUint(text, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(parsed)
+	default:
+		return GetErrorf(CSVUnsupportedType, value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,621 @@
+package PointerList
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+/////////////////////////////////
+//        Cache Tag List       //
+/////////////////////////////////
+
+//Counters of a CacheTagList.
+type CacheStats struct {
+	//Get, GetNext and Contains that found an element.
+	Hits uint64
+	//Get, GetNext and Contains that found none.
+	Misses uint64
+	//Elements removed to stay within the limits.
+	Evictions uint64
+}
+
+type CacheOption func(o *cacheOptions)
+
+type cacheOptions struct {
+	perKeyLimit int
+}
+
+//Limits the number of elements of every tag. The least used element of the tag is evicted first.
+func WithPerKeyLimit(limit int) CacheOption {
+	return func(o *cacheOptions) {
+		o.perKeyLimit = limit
+	}
+}
+
+//GuardedTagList that evicts the least used element once a limit is exceeded. Get, GetNext and Contains count as
+//a use, Get uses every element of the tag. Elements added through the lists returned by Get are not tracked.
+type CacheTagList[T any] interface {
+	GuardedTagList[T]
+
+	//Returns the hit, miss and eviction counters.
+	Stats() CacheStats
+}
+
+type cacheTagList[T any] struct {
+	*guardedTagList[T]
+
+	cacheLocker sync.Mutex
+	maxItems    int
+	options     cacheOptions
+	f           SortPointerFunc[cacheEntry[T]]
+	tick        uint64
+	entries     map[cacheKey[T]]*cacheEntry[T]
+	queue       *priorityList[cacheEntry[T]]
+	keyQueues   map[string]*priorityList[cacheEntry[T]]
+	stats       CacheStats
+
+	//Elements expired by AddWithTTL, untracked by the next method of the cache. Expiry may happen while the cache
+	//is locked, so they have a lock of their own.
+	expireLocker sync.Mutex
+	expired      []cacheKey[T]
+	onExpire     ExpireTagFunc[T]
+	expiring     expireQueue
+}
+
+type cacheKey[T any] struct {
+	key  string
+	item *T
+}
+
+type cacheEntry[T any] struct {
+	cacheKey[T]
+	//Tick of the last use.
+	used uint64
+	uses uint64
+}
+
+//Evicts the least recently used element once more than maxItems are stored. A maxItems < 1 leaves only the per key limit.
+func NewLRUTagList[T any](maxItems int, options ...CacheOption) CacheTagList[T] {
+	return newCacheTagList(maxItems, options, func(left *cacheEntry[T], right *cacheEntry[T]) bool {
+		return left.used > right.used
+	})
+}
+
+//Evicts the least frequently used element once more than maxItems are stored, the least recently used of them on a tie.
+//A maxItems < 1 leaves only the per key limit.
+func NewLFUTagList[T any](maxItems int, options ...CacheOption) CacheTagList[T] {
+	return newCacheTagList(maxItems, options, func(left *cacheEntry[T], right *cacheEntry[T]) bool {
+		if left.uses != right.uses {
+			return left.uses > right.uses
+		}
+
+		return left.used > right.used
+	})
+}
+
+func (c *cacheTagList[T]) Get(key string) GuardedPointerList[T] {
+	c.lock()
+	defer c.unlock()
+
+	list := c.guardedTagList.Get(key)
+	if list == nil || list.Count() == 0 {
+		c.stats.Misses++
+		return list
+	}
+
+	c.stats.Hits++
+
+	for _, item := range snapshotOf[T](list) {
+		c.use(key, item)
+	}
+
+	return list
+}
+
+func (c *cacheTagList[T]) GetNext(key string) *T {
+	c.lock()
+	defer c.unlock()
+
+	item := c.guardedTagList.GetNext(key)
+	if item == nil {
+		c.stats.Misses++
+		return nil
+	}
+
+	c.stats.Hits++
+	c.use(key, item)
+
+	return item
+}
+
+//Determines whether a tag is in the CacheTagList.
+func (c *cacheTagList[T]) Contains(key string, value *T) bool {
+	c.lock()
+	defer c.unlock()
+
+	if !c.guardedTagList.Contains(key, value) {
+		c.stats.Misses++
+		return false
+	}
+
+	c.stats.Hits++
+	c.use(key, value)
+
+	return true
+}
+
+//Adds a tag with the specified key and value to the list, then evicts the least used elements over the limits.
+//Returns the element removed by the limit of the tag, see SetKeyLimit.
+func (c *cacheTagList[T]) Add(key string, value *T) (*T, error) {
+	c.lock()
+	defer c.unlock()
+
+	evicted, err := c.guardedTagList.Add(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	c.admitted(key, value, evicted)
+
+	return evicted, nil
+}
+
+//Inserts an element into the CacheTagList at the specified index, then evicts the least used elements over the limits.
+//Returns the element removed by the limit of the tag, see SetKeyLimit.
+func (c *cacheTagList[T]) Insert(index int, key string, value *T) (*T, error) {
+	c.lock()
+	defer c.unlock()
+
+	evicted, err := c.guardedTagList.Insert(index, key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	c.admitted(key, value, evicted)
+
+	return evicted, nil
+}
+
+//Adds a tag with the specified key and value if the tag is still at version, then evicts the least used elements over the limits.
+func (c *cacheTagList[T]) AddIfVersion(key string, version uint64, value *T) error {
+	c.lock()
+	defer c.unlock()
+
+	if err := c.guardedTagList.AddIfVersion(key, version, value); err != nil {
+		return err
+	}
+
+	c.evict(key, c.use(key, value))
+
+	return nil
+}
+
+//Replaces the element of the tag at the specified index if the tag is still at version. The new element is used.
+func (c *cacheTagList[T]) SetIfVersion(key string, version uint64, index int, value *T) error {
+	c.lock()
+	defer c.unlock()
+
+	var replaced *T
+	if list := c.guardedTagList.Get(key); list != nil {
+		replaced = list.Get(index)
+	}
+
+	if err := c.guardedTagList.SetIfVersion(key, version, index, value); err != nil {
+		return err
+	}
+
+	c.admitted(key, value, replaced)
+
+	return nil
+}
+
+//Replaces the elements of the tag if the tag is still at version, then evicts the least used elements over the limits.
+func (c *cacheTagList[T]) ReplaceIfVersion(key string, version uint64, items []*T) error {
+	c.lock()
+	defer c.unlock()
+
+	if err := c.guardedTagList.ReplaceIfVersion(key, version, items); err != nil {
+		return err
+	}
+
+	for k, entry := range c.entries {
+		if k.key == key {
+			c.forget(entry)
+		}
+	}
+
+	for _, item := range items {
+		c.use(key, item)
+	}
+
+	c.evict(key, nil)
+
+	return nil
+}
+
+//Adds a tag with the specified key and value to the list, then evicts the least used elements over the limits.
+//Returns false if the lock could not be taken within timeout, or false and a KeyLimit error if the tag is at its limit with LimitReject.
+func (c *cacheTagList[T]) TryAdd(key string, value *T, timeout time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	if !tryLock(&c.cacheLocker, timeout) {
+		return false, nil
+	}
+	defer c.unlock()
+
+	c.prune()
+
+	evicted, ok, err := c.guardedTagList.tryAdd(key, value, time.Until(deadline))
+	if !ok {
+		return false, err
+	}
+
+	c.admitted(key, value, evicted)
+
+	return true, nil
+}
+
+//Adds a tag with the specified key and value that is removed once d passed without a Touch.
+func (c *cacheTagList[T]) AddWithTTL(key string, value *T, d time.Duration) error {
+	c.lock()
+	defer c.unlock()
+
+	if err := c.guardedTagList.AddWithTTL(key, value, d); err != nil {
+		return err
+	}
+
+	c.evict(key, c.use(key, value))
+
+	return nil
+}
+
+//Removes the first occurrence of a specific object from the CacheTagList.
+func (c *cacheTagList[T]) Remove(key string, value *T) bool {
+	c.lock()
+	defer c.unlock()
+
+	if !c.guardedTagList.Remove(key, value) {
+		return false
+	}
+
+	c.release(key, value)
+
+	return true
+}
+
+//Removes the element at the specified index of the CacheTagList.
+func (c *cacheTagList[T]) RemoveAt(key string, index int) bool {
+	c.lock()
+	defer c.unlock()
+
+	list := c.guardedTagList.Get(key)
+	if list == nil {
+		return false
+	}
+
+	item := list.Get(index)
+
+	if !c.guardedTagList.RemoveAt(key, index) {
+		return false
+	}
+
+	c.release(key, item)
+
+	return true
+}
+
+//Removes target list from the CacheTagList.
+func (c *cacheTagList[T]) ClearList(key string) {
+	c.lock()
+	defer c.unlock()
+
+	c.guardedTagList.ClearList(key)
+
+	for k, entry := range c.entries {
+		if k.key == key {
+			c.forget(entry)
+		}
+	}
+
+	delete(c.keyQueues, key)
+}
+
+//Removes all elements from the CacheTagList.
+func (c *cacheTagList[T]) Clear() {
+	c.lock()
+	defer c.unlock()
+
+	c.guardedTagList.Clear()
+	c.reset()
+}
+
+//Loop. removeCurItem also stops tracking the element.
+func (c *cacheTagList[T]) Foreach(f ForeachTagListFunc[T]) {
+	c.lock()
+	defer c.unlock()
+
+	removed := make([]cacheKey[T], 0)
+	c.guardedTagList.Foreach(trackedForeach(f, &removed))
+	c.releaseAll(removed)
+}
+
+//Loop. removeCurItem also stops tracking the element. Returns ctx.Err() if the context is done.
+func (c *cacheTagList[T]) ForeachCtx(ctx context.Context, f ForeachTagListFunc[T]) error {
+	c.lock()
+	defer c.unlock()
+
+	removed := make([]cacheKey[T], 0)
+	err := c.guardedTagList.ForeachCtx(ctx, trackedForeach(f, &removed))
+	c.releaseAll(removed)
+
+	return err
+}
+
+//Replaces the tags with a snapshot written by WriteTo. The elements are tracked in the order of their tags and evicted over the limits.
+func (c *cacheTagList[T]) ReadFrom(r io.Reader) (int64, error) {
+	c.lock()
+	defer c.unlock()
+
+	n, err := c.guardedTagList.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+
+	c.retrack()
+
+	return n, nil
+}
+
+//Replaces the tags with a decoded JSON object. The elements are tracked in the order of their tags and evicted over the limits.
+func (c *cacheTagList[T]) UnmarshalJSON(data []byte) error {
+	c.lock()
+	defer c.unlock()
+
+	if err := c.guardedTagList.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	c.retrack()
+
+	return nil
+}
+
+//Adds the rows of a CSV with a leading "tag" column, every row is added as Add. The limits of the tags are checked
+//before the first row is added, so nothing is added if a row does not fit.
+func (c *cacheTagList[T]) ImportCSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, ',', build, c.guardedTagList.fits, c.Add)
+}
+
+//Same as ImportCSV, separated by tabs.
+func (c *cacheTagList[T]) ImportTSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, '\t', build, c.guardedTagList.fits, c.Add)
+}
+
+//Sets the func called with every expired element, after the cache was released, so it may use the cache.
+func (c *cacheTagList[T]) OnExpire(f ExpireTagFunc[T]) {
+	c.expireLocker.Lock()
+	defer c.expireLocker.Unlock()
+
+	c.onExpire = f
+}
+
+//Returns the hit, miss and eviction counters.
+func (c *cacheTagList[T]) Stats() CacheStats {
+	c.lock()
+	defer c.unlock()
+
+	return c.stats
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func newCacheTagList[T any](maxItems int, options []CacheOption, f SortPointerFunc[cacheEntry[T]]) *cacheTagList[T] {
+	c := &cacheTagList[T]{
+		guardedTagList: &guardedTagList[T]{
+			mapList: make(map[string]GuardedPointerList[T]),
+		},
+		maxItems: maxItems,
+		f:        f,
+	}
+
+	for _, option := range options {
+		option(&c.options)
+	}
+
+	c.reset()
+	c.guardedTagList.onExpire = c.expire
+
+	return c
+}
+
+//Locks the cache and stops tracking the elements that expired since the last method.
+func (c *cacheTagList[T]) lock() {
+	c.cacheLocker.Lock()
+	c.expiring.hold()
+	c.prune()
+}
+
+//Releases the cache, then calls the OnExpire funcs of the elements that expired meanwhile.
+func (c *cacheTagList[T]) unlock() {
+	expired := c.expiring.take()
+	c.cacheLocker.Unlock()
+
+	callAll(expired)
+}
+
+//Stops tracking the expired elements. The cache is locked.
+func (c *cacheTagList[T]) prune() {
+	for {
+		c.expireLocker.Lock()
+		expired := c.expired
+		c.expired = nil
+		c.expireLocker.Unlock()
+
+		if len(expired) == 0 {
+			return
+		}
+
+		//Contains may expire more elements, they are pruned by the next pass.
+		c.releaseAll(expired)
+	}
+}
+
+//Queues the expired element for prune, then calls the OnExpire func once the cache was released.
+func (c *cacheTagList[T]) expire(key string, item *T) {
+	c.expireLocker.Lock()
+	c.expired = append(c.expired, cacheKey[T]{key: key, item: item})
+	f := c.onExpire
+	c.expireLocker.Unlock()
+
+	if f != nil {
+		c.expiring.call(func() {
+			f(key, item)
+		})
+	}
+}
+
+func (c *cacheTagList[T]) reset() {
+	c.entries = make(map[cacheKey[T]]*cacheEntry[T])
+	c.queue = c.newQueue()
+	c.keyQueues = make(map[string]*priorityList[cacheEntry[T]])
+}
+
+func (c *cacheTagList[T]) newQueue() *priorityList[cacheEntry[T]] {
+	return &priorityList[cacheEntry[T]]{
+		heap:    make([]*cacheEntry[T], 0),
+		indexOf: make(map[*cacheEntry[T]]int),
+		f:       c.f,
+	}
+}
+
+//Marks the element as used, tracking it if it is not yet.
+func (c *cacheTagList[T]) use(key string, item *T) *cacheEntry[T] {
+	k := cacheKey[T]{key: key, item: item}
+
+	entry := c.entries[k]
+	if entry == nil {
+		entry = &cacheEntry[T]{cacheKey: k}
+		c.entries[k] = entry
+	}
+
+	c.tick++
+	entry.used = c.tick
+	entry.uses++
+
+	c.queue.Push(entry)
+
+	if c.options.perKeyLimit > 0 {
+		if c.keyQueues[key] == nil {
+			c.keyQueues[key] = c.newQueue()
+		}
+
+		c.keyQueues[key].Push(entry)
+	}
+
+	return entry
+}
+
+func (c *cacheTagList[T]) forget(entry *cacheEntry[T]) {
+	c.queue.RemoveItem(entry)
+
+	if queue := c.keyQueues[entry.key]; queue != nil {
+		queue.RemoveItem(entry)
+	}
+
+	delete(c.entries, entry.cacheKey)
+}
+
+//Stops tracking an element that was removed, unless another occurrence is left.
+func (c *cacheTagList[T]) release(key string, item *T) {
+	entry := c.entries[cacheKey[T]{key: key, item: item}]
+	if entry != nil && !c.guardedTagList.Contains(key, item) {
+		c.forget(entry)
+	}
+}
+
+func (c *cacheTagList[T]) releaseAll(removed []cacheKey[T]) {
+	for _, k := range removed {
+		c.release(k.key, k.item)
+	}
+}
+
+//Tracks the added element and evicts over the limits. evicted was removed by the limit of the tag.
+func (c *cacheTagList[T]) admitted(key string, value *T, evicted *T) {
+	if evicted != nil {
+		c.release(key, evicted)
+	}
+
+	c.evict(key, c.use(key, value))
+}
+
+//Tracks every element again after the tags were replaced.
+func (c *cacheTagList[T]) retrack() {
+	c.reset()
+	mapItems := c.guardedTagList.ToArrayMap()
+
+	for key, items := range mapItems {
+		for _, item := range items {
+			c.use(key, item)
+		}
+	}
+
+	for key := range mapItems {
+		c.evict(key, nil)
+	}
+}
+
+//Evicts the least used elements of the tag and of the list until both are within their limits. added is not evicted.
+func (c *cacheTagList[T]) evict(key string, added *cacheEntry[T]) {
+	if c.options.perKeyLimit > 0 {
+		for list := c.guardedTagList.Get(key); list != nil && list.Count() > c.options.perKeyLimit; {
+			if !c.evictFrom(c.keyQueues[key], added) {
+				break
+			}
+		}
+	}
+
+	if c.maxItems > 0 {
+		//Counted once, each eviction removes one element.
+		for count := c.guardedTagList.TotalCount(); count > c.maxItems && c.evictFrom(c.queue, added); count-- {
+		}
+	}
+}
+
+//Removes the first element of the queue that is still in its tag. Returns false if there is none.
+func (c *cacheTagList[T]) evictFrom(queue *priorityList[cacheEntry[T]], added *cacheEntry[T]) bool {
+	if queue == nil {
+		return false
+	}
+
+	if added != nil && queue.Peek() == added {
+		queue.Pop()
+		defer queue.Push(added)
+	}
+
+	for entry := queue.Peek(); entry != nil; entry = queue.Peek() {
+		c.forget(entry)
+
+		//Elements removed through the lists returned by Get, or expired since the last prune, are still queued.
+		if c.guardedTagList.Remove(entry.key, entry.item) {
+			c.stats.Evictions++
+			return true
+		}
+	}
+
+	return false
+}
+
+func trackedForeach[T any](f ForeachTagListFunc[T], removed *[]cacheKey[T]) ForeachTagListFunc[T] {
+	return func(key string, index int, current *T, removeCurItem func()) bool {
+		removeItem := func() {
+			removeCurItem()
+			*removed = append(*removed, cacheKey[T]{key: key, item: current})
+		}
+
+		return f(key, index, current, removeItem)
+	}
+}
@@ -0,0 +1,123 @@
+package PointerList
+
+import (
+	"sync"
+	"time"
+)
+
+/////////////////////////////////
+//            Clock            //
+/////////////////////////////////
+
+//Source of time for the time based lists, so tests can replace it with a ManualClock.
+type Clock interface {
+	Now() time.Time
+	//Returns a channel that receives the current time once the clock reached at, and a func that stops the timer.
+	//The func returns false if the time was sent already or the timer was stopped.
+	NewTimer(at time.Time) (<-chan time.Time, func() bool)
+}
+
+//Clock of the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(at time.Time) (<-chan time.Time, func() bool) {
+	timer := time.NewTimer(time.Until(at))
+	return timer.C, timer.Stop
+}
+
+//Clock that only moves when Advance or Set is called.
+type ManualClock struct {
+	locker  sync.Mutex
+	now     time.Time
+	waiters []manualWaiter
+}
+
+type manualWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{
+		now: now,
+	}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	return c.now
+}
+
+//Sends the time once the clock was moved to at or later. Stopping the timer drops its waiter.
+func (c *ManualClock) NewTimer(at time.Time) (<-chan time.Time, func() bool) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	ch := make(chan time.Time, 1)
+
+	if !at.After(c.now) {
+		ch <- c.now
+		return ch, func() bool { return false }
+	}
+
+	c.waiters = append(c.waiters, manualWaiter{at: at, ch: ch})
+
+	return ch, func() bool {
+		return c.stop(ch)
+	}
+}
+
+//Moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	c.set(c.now.Add(d))
+}
+
+//Moves the clock to now.
+func (c *ManualClock) Set(now time.Time) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	c.set(now)
+}
+
+func (c *ManualClock) set(now time.Time) {
+	c.now = now
+	waiters := c.waiters[:0]
+
+	for _, waiter := range c.waiters {
+		if waiter.at.After(now) {
+			waiters = append(waiters, waiter)
+		} else {
+			waiter.ch <- now
+		}
+	}
+
+	sliceClear(c.waiters[len(waiters):])
+	c.waiters = waiters
+}
+
+//Removes the waiter of the channel. Returns false if it was sent already.
+func (c *ManualClock) stop(ch chan time.Time) bool {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	for i, waiter := range c.waiters {
+		if waiter.ch == ch {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
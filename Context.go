@@ -0,0 +1,64 @@
+package PointerList
+
+import "context"
+
+//Number of elements visited between two checks of the context.
+const ctxCheckInterval = 256
+
+//Returns ctx.Err() on every ctxCheckInterval. iteration.
+func checkCtx(ctx context.Context, iteration int) error {
+	if iteration%ctxCheckInterval != 0 {
+		return nil
+	}
+
+	return ctx.Err()
+}
+
+//An element removed by removeCurItem of a ForeachCtx walk, applied once the walk completed.
+type tagRemoval[T any] struct {
+	list  PointerList[T]
+	key   string
+	index int
+}
+
+//Walks the tags, collecting the elements removed by f in the order they were visited. The index passed to f is the
+//index at the start of the walk, since nothing is removed before it completed.
+func foreachTagsCtx[T any, L PointerList[T]](ctx context.Context, mapList map[string]L, f ForeachTagListFunc[T]) ([]tagRemoval[T], error) {
+	removals := make([]tagRemoval[T], 0)
+	visited := 0
+
+	for key, tagged := range mapList {
+		list := PointerList[T](tagged)
+		if list == nil {
+			continue
+		}
+
+		for i := 0; i < list.Count(); i++ {
+			if err := checkCtx(ctx, visited); err != nil {
+				return nil, err
+			}
+			visited++
+
+			removeItem := func() {
+				if n := len(removals); n > 0 && removals[n-1].list == list && removals[n-1].index == i {
+					return
+				}
+
+				removals = append(removals, tagRemoval[T]{list: list, key: key, index: i})
+			}
+
+			if !f(key, i, list.Get(i), removeItem) {
+				return removals, nil
+			}
+		}
+	}
+
+	return removals, nil
+}
+
+//Removes the collected elements, the last first so the indexes of the others stay valid.
+func applyTagRemovals[T any](removals []tagRemoval[T]) {
+	for i := len(removals) - 1; i >= 0; i-- {
+		removals[i].list.RemoveAtNoSafe(removals[i].index)
+	}
+}
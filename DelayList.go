@@ -0,0 +1,195 @@
+package PointerList
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/////////////////////////////////
+//          Delay List         //
+/////////////////////////////////
+
+//Elements scheduled for a point in time, ordered by due time. Safe for concurrent use.
+type DelayList[T any] interface {
+	//Schedules the element at the specified time. A scheduled element is rescheduled, nil is ignored.
+	Schedule(item *T, at time.Time)
+	//Removes and returns every element due at now, the earliest first.
+	PopDue(now time.Time) []*T
+	//Returns the time of the earliest element. Returns false if the list is empty.
+	NextDue() (time.Time, bool)
+	//Moves the element to the specified time. Returns false if it is not scheduled.
+	Reschedule(item *T, at time.Time) bool
+	//Removes the element. Returns false if it is not scheduled.
+	Cancel(item *T) bool
+	//Waits until at least one element is due by the Clock, then removes and returns every due element. Returns ctx.Err() if the context is done.
+	WaitDue(ctx context.Context) ([]*T, error)
+	//Returns the number of elements in a sequence.
+	Count() int
+}
+
+type delayList[T any] struct {
+	locker sync.Mutex
+	clock  Clock
+	due    map[*T]time.Time
+	queue  *priorityList[T]
+	//Closed and replaced when an element is scheduled, so WaitDue can wait for an earlier time.
+	scheduled chan struct{}
+}
+
+//A nil clock is SystemClock.
+func NewDelayList[T any](clock Clock) DelayList[T] {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	l := &delayList[T]{
+		clock:     clock,
+		due:       make(map[*T]time.Time),
+		scheduled: make(chan struct{}),
+	}
+
+	l.queue = &priorityList[T]{
+		heap:    make([]*T, 0),
+		indexOf: make(map[*T]int),
+		f: func(left *T, right *T) bool {
+			return l.due[left].After(l.due[right])
+		},
+	}
+
+	return l
+}
+
+//Schedules the element at the specified time. A scheduled element is rescheduled, nil is ignored.
+func (l *delayList[T]) Schedule(item *T, at time.Time) {
+	if item == nil {
+		return
+	}
+
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	l.due[item] = at
+	l.queue.Push(item)
+	l.notify()
+}
+
+//Removes and returns every element due at now, the earliest first.
+func (l *delayList[T]) PopDue(now time.Time) []*T {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	return l.popDue(now)
+}
+
+//Returns the time of the earliest element. Returns false if the list is empty.
+func (l *delayList[T]) NextDue() (time.Time, bool) {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	return l.nextDue()
+}
+
+//Moves the element to the specified time. Returns false if it is not scheduled.
+func (l *delayList[T]) Reschedule(item *T, at time.Time) bool {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	if _, ok := l.due[item]; !ok {
+		return false
+	}
+
+	l.due[item] = at
+	l.queue.Update(item)
+	l.notify()
+
+	return true
+}
+
+//Removes the element. Returns false if it is not scheduled.
+func (l *delayList[T]) Cancel(item *T) bool {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	if !l.queue.RemoveItem(item) {
+		return false
+	}
+
+	delete(l.due, item)
+
+	return true
+}
+
+//Waits until at least one element is due by the Clock, then removes and returns every due element. Returns ctx.Err() if the context is done.
+func (l *delayList[T]) WaitDue(ctx context.Context) ([]*T, error) {
+	for {
+		l.locker.Lock()
+
+		now := l.clock.Now()
+
+		if items := l.popDue(now); len(items) > 0 {
+			l.locker.Unlock()
+			return items, nil
+		}
+
+		scheduled := l.scheduled
+		next, ok := l.nextDue()
+
+		l.locker.Unlock()
+
+		//A nil channel blocks, the list is empty until the next Schedule.
+		var timer <-chan time.Time
+		stop := func() bool { return false }
+
+		if ok {
+			timer, stop = l.clock.NewTimer(next)
+		}
+
+		select {
+		case <-ctx.Done():
+			stop()
+			return nil, ctx.Err()
+		case <-scheduled:
+		case <-timer:
+		}
+
+		stop()
+	}
+}
+
+//Returns the number of elements in a sequence.
+func (l *delayList[T]) Count() int {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	return len(l.due)
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func (l *delayList[T]) popDue(now time.Time) []*T {
+	items := make([]*T, 0)
+
+	for next := l.queue.Peek(); next != nil && !l.due[next].After(now); next = l.queue.Peek() {
+		items = append(items, l.queue.Pop())
+		delete(l.due, next)
+	}
+
+	return items
+}
+
+func (l *delayList[T]) nextDue() (time.Time, bool) {
+	next := l.queue.Peek()
+	if next == nil {
+		return time.Time{}, false
+	}
+
+	return l.due[next], true
+}
+
+func (l *delayList[T]) notify() {
+	close(l.scheduled)
+	l.scheduled = make(chan struct{})
+}
@@ -0,0 +1,414 @@
+package PointerList
+
+import (
+	"sync"
+	"time"
+)
+
+/////////////////////////////////
+//            Deque            //
+/////////////////////////////////
+
+//PointerList[T] backed by a growable ring buffer. Push and Pop at both ends are amortised O(1),
+//the other PointerList[T] methods first move the ring into a plain array, which is O(n) after the front moved.
+type Deque[T any] interface {
+	PointerList[T]
+
+	//Adds an object to the front.
+	PushFront(item *T)
+	//Adds an object to the back, same as Add.
+	PushBack(item *T)
+	//Removes and returns the front element, or nil.
+	PopFront() *T
+	//Removes and returns the back element, or nil.
+	PopBack() *T
+	//Returns the front element without removing it, or nil.
+	PeekFront() *T
+	//Returns the back element without removing it, or nil.
+	PeekBack() *T
+}
+
+//Deque[T] protected by mutex.
+type GuardedDeque[T any] interface {
+	Deque[T]
+	GuardedPointerList[T]
+}
+
+//Deque[T] of a fixed capacity. Adding to a full RingBuffer[T] overwrites the element at the other end:
+//PushBack, Add and Insert drop the front element, PushFront drops the back element.
+type RingBuffer[T any] interface {
+	Deque[T]
+
+	//Maximum number of elements.
+	Capacity() int
+}
+
+//RingBuffer[T] protected by mutex.
+type GuardedRingBuffer[T any] interface {
+	RingBuffer[T]
+	GuardedPointerList[T]
+}
+
+type deque[T any] struct {
+	*pointerList[T]
+	base *dequeBase[T]
+}
+
+func NewDeque[T any]() Deque[T] {
+	return newDeque[T](0, false)
+}
+
+func NewGuardedDeque[T any]() GuardedDeque[T] {
+	return newDeque[T](0, true)
+}
+
+//A capacity < 1 is stored as 1.
+func NewRingBuffer[T any](capacity int) RingBuffer[T] {
+	return newDeque[T](maxInt(capacity, 1), false)
+}
+
+//A capacity < 1 is stored as 1.
+func NewGuardedRingBuffer[T any](capacity int) GuardedRingBuffer[T] {
+	return newDeque[T](maxInt(capacity, 1), true)
+}
+
+//Adds an object to the front.
+func (d *deque[T]) PushFront(item *T) {
+	if d.ring(func() { d.base.pushFront(item) }) {
+		return
+	}
+
+	d.start()
+	defer d.end()
+
+	if d.base.capacity > 0 && len(d.list) == d.base.capacity {
+		d.removeAt(len(d.list) - 1)
+	}
+
+	if d.indexRejects(item) == nil {
+		d.list, _ = sliceInsert(d.list, item, 0)
+		d.indexAdd(item, 0)
+	}
+}
+
+//Adds an object to the back, same as Add.
+func (d *deque[T]) PushBack(item *T) {
+	if d.ring(func() { d.base.pushBack(item) }) {
+		return
+	}
+
+	d.Add(item)
+}
+
+//Removes and returns the front element, or nil.
+func (d *deque[T]) PopFront() *T {
+	var item *T
+
+	if d.ring(func() { item = d.base.popFront() }) {
+		return item
+	}
+
+	return d.FindAndRemove(func(index int, current *T) bool {
+		return true
+	})
+}
+
+//Removes and returns the back element, or nil.
+func (d *deque[T]) PopBack() *T {
+	var item *T
+
+	if d.ring(func() { item = d.base.popBack() }) {
+		return item
+	}
+
+	d.start()
+	defer d.end()
+
+	if len(d.list) == 0 {
+		return nil
+	}
+
+	item = d.list[len(d.list)-1]
+	d.removeAt(len(d.list) - 1)
+
+	return item
+}
+
+//Returns the front element without removing it, or nil.
+func (d *deque[T]) PeekFront() *T {
+	var item *T
+
+	if d.ring(func() { item = d.base.at(0) }) {
+		return item
+	}
+
+	return d.Get(0)
+}
+
+//Returns the back element without removing it, or nil.
+func (d *deque[T]) PeekBack() *T {
+	var item *T
+
+	if d.ring(func() { item = d.base.at(d.base.count - 1) }) {
+		return item
+	}
+
+	d.start()
+	defer d.end()
+
+	if len(d.list) == 0 {
+		return nil
+	}
+
+	return d.list[len(d.list)-1]
+}
+
+//Maximum number of elements.
+func (d *deque[T]) Capacity() int {
+	return d.base.capacity
+}
+
+//Removes the first occurrence of a specific object from the Deque[T]. NoSafe
+func (d *deque[T]) RemoveNoSafe(targetItem *T) bool {
+	d.base.linearize()
+	defer d.base.absorb()
+
+	return d.remove(targetItem)
+}
+
+//Removes the element at the specified index of the Deque[T]. NoSafe
+func (d *deque[T]) RemoveAtNoSafe(index int) bool {
+	d.base.linearize()
+	defer d.base.absorb()
+
+	return d.removeAt(index)
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//Runs f on the ring under the lock and reports true. Reports false without running f if indexes
+//or expiry need the PointerList[T] methods instead.
+func (d *deque[T]) ring(f func()) bool {
+	d.base.lock()
+	defer d.base.unlock()
+
+	if d.indexed() || d.base.hook != nil {
+		return false
+	}
+
+	f()
+	return true
+}
+
+func newDeque[T any](capacity int, guarded bool) *deque[T] {
+	l := &pointerList[T]{
+		list: make([]*T, 0),
+	}
+
+	base := &dequeBase[T]{
+		owner:    l,
+		capacity: capacity,
+		guarded:  guarded,
+		buf:      make([]*T, capacity),
+	}
+
+	l.BASE = base
+
+	return &deque[T]{
+		pointerList: l,
+		base:        base,
+	}
+}
+
+//BASE of a deque. The elements live in buf, from head on and wrapping around. start moves them into
+//owner.list for the PointerList[T] methods, end takes owner.list back as the ring.
+type dequeBase[T any] struct {
+	BASE
+	owner    *pointerList[T]
+	hook     baseHook
+	locker   sync.Mutex
+	guarded  bool
+	capacity int
+	buf      []*T
+	head     int
+	count    int
+}
+
+func (b *dequeBase[T]) start() {
+	b.lock()
+	b.linearize()
+
+	if b.hook != nil {
+		b.hook.started()
+	}
+}
+
+func (b *dequeBase[T]) end() {
+	//absorb drops the elements of a full ring, before the hook so they belong to the same step.
+	b.absorb()
+
+	var after func()
+	if b.hook != nil {
+		after = b.hook.released()
+	}
+
+	b.unlock()
+
+	if after != nil {
+		after()
+	}
+}
+
+func (b *dequeBase[T]) tryStart(timeout time.Duration) bool {
+	if b.guarded && !tryLock(&b.locker, timeout) {
+		return false
+	}
+
+	b.linearize()
+
+	if b.hook != nil {
+		b.hook.started()
+	}
+
+	return true
+}
+
+func (b *dequeBase[T]) setHook(hook baseHook) {
+	b.hook = hook
+}
+
+func (b *dequeBase[T]) lock() {
+	if b.guarded {
+		b.locker.Lock()
+	}
+}
+
+func (b *dequeBase[T]) unlock() {
+	if b.guarded {
+		b.locker.Unlock()
+	}
+}
+
+//Moves the ring to the start of buf and hands it to the owner.
+func (b *dequeBase[T]) linearize() {
+	if b.head+b.count > len(b.buf) {
+		newBuf := make([]*T, len(b.buf))
+		n := copy(newBuf, b.buf[b.head:])
+		copy(newBuf[n:], b.buf[:b.count-n])
+
+		b.buf = newBuf
+		b.head = 0
+	} else if b.head > 0 {
+		copy(b.buf, b.buf[b.head:b.head+b.count])
+		sliceClear(b.buf[b.count : b.head+b.count])
+
+		b.head = 0
+	}
+
+	b.owner.list = b.buf[:b.count]
+}
+
+//Takes the array of the owner back as the ring. A RingBuffer[T] drops the front elements that do not fit.
+func (b *dequeBase[T]) absorb() {
+	list := b.owner.list
+
+	if b.capacity > 0 {
+		for len(list) > b.capacity {
+			b.owner.indexRemove(list[0], 0)
+			list = list[1:]
+		}
+
+		if cap(list) != b.capacity {
+			newBuf := make([]*T, b.capacity)
+			copy(newBuf, list)
+			list = newBuf[:len(list)]
+		}
+	}
+
+	b.buf = list[:cap(list)]
+	b.head = 0
+	b.count = len(list)
+	b.owner.list = list
+}
+
+func (b *dequeBase[T]) at(index int) *T {
+	if index < 0 || index >= b.count {
+		return nil
+	}
+
+	return b.buf[(b.head+index)%len(b.buf)]
+}
+
+func (b *dequeBase[T]) pushBack(item *T) {
+	b.owner.version++
+
+	if b.count == len(b.buf) {
+		if b.capacity > 0 {
+			b.buf[b.head] = item
+			b.head = (b.head + 1) % len(b.buf)
+			return
+		}
+
+		b.grow()
+	}
+
+	b.buf[(b.head+b.count)%len(b.buf)] = item
+	b.count++
+}
+
+func (b *dequeBase[T]) pushFront(item *T) {
+	b.owner.version++
+
+	if b.count == len(b.buf) {
+		if b.capacity > 0 {
+			b.head = (b.head - 1 + len(b.buf)) % len(b.buf)
+			b.buf[b.head] = item
+			return
+		}
+
+		b.grow()
+	}
+
+	b.head = (b.head - 1 + len(b.buf)) % len(b.buf)
+	b.buf[b.head] = item
+	b.count++
+}
+
+func (b *dequeBase[T]) popFront() *T {
+	if b.count == 0 {
+		return nil
+	}
+
+	item := b.buf[b.head]
+	b.buf[b.head] = nil
+	b.head = (b.head + 1) % len(b.buf)
+	b.count--
+	b.owner.version++
+
+	return item
+}
+
+func (b *dequeBase[T]) popBack() *T {
+	if b.count == 0 {
+		return nil
+	}
+
+	index := (b.head + b.count - 1) % len(b.buf)
+	item := b.buf[index]
+	b.buf[index] = nil
+	b.count--
+	b.owner.version++
+
+	return item
+}
+
+func (b *dequeBase[T]) grow() {
+	newBuf := make([]*T, maxInt(4, 2*len(b.buf)))
+	n := copy(newBuf, b.buf[b.head:minInt(b.head+b.count, len(b.buf))])
+	copy(newBuf[n:], b.buf[:b.count-n])
+
+	b.buf = newBuf
+	b.head = 0
+}
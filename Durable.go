@@ -0,0 +1,1155 @@
+package PointerList
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+/////////////////////////////////
+//      Durable Tag List       //
+/////////////////////////////////
+
+//Log layout:
+//	magic "GPLW" | version (1 byte) | generation (8 bytes, big endian) | records
+//Record:
+//	payload length (4 bytes, big endian) | crc32 of payload (4 bytes, big endian) | payload
+//Payload:
+//	op (1 byte) | uvarint key length | key | uvarint index (Insert, RemoveAt) | item (Add, Insert)
+//Item:
+//	uvarint id, 0 => nil | uvarint length | Codec[T] bytes
+//
+//The snapshot file (path + ".snap") is the generation (8 bytes, big endian) followed by a tag snapshot.
+//Compaction writes the snapshot of the next generation, then restarts the log with that generation, so a log
+//older than the snapshot is known to be included in it. Ids are the refs of the snapshot objects, an element
+//logged with a known id is the same pointer after replay.
+const (
+	walMagic   = "GPLW"
+	walVersion = 1
+
+	walOpAdd       byte = 1
+	walOpInsert    byte = 2
+	walOpRemoveAt  byte = 3
+	walOpClearList byte = 4
+	walOpClear     byte = 5
+)
+
+//When the log is flushed to disk.
+type SyncPolicy int
+
+const (
+	//Every mutation is flushed before it is applied.
+	SyncAlways SyncPolicy = iota
+	//The log is flushed periodically by a background goroutine.
+	SyncInterval
+	//Flushing is left to the operating system.
+	SyncNever
+)
+
+type DurableOption func(o *durableOptions)
+
+type durableOptions struct {
+	sync            SyncPolicy
+	syncInterval    time.Duration
+	compactInterval time.Duration
+}
+
+//Sets the SyncPolicy. The default is SyncAlways.
+func WithSync(policy SyncPolicy) DurableOption {
+	return func(o *durableOptions) {
+		o.sync = policy
+	}
+}
+
+//Flushes the log every interval.
+func WithSyncInterval(interval time.Duration) DurableOption {
+	return func(o *durableOptions) {
+		o.sync = SyncInterval
+		o.syncInterval = interval
+	}
+}
+
+//Compacts the log every interval.
+func WithCompactInterval(interval time.Duration) DurableOption {
+	return func(o *durableOptions) {
+		o.compactInterval = interval
+	}
+}
+
+//GuardedTagList that appends every Add, Insert, Remove, RemoveAt, ClearList and Clear, and the ...IfVersion methods,
+//to a write-ahead log before it is applied. Versions are not logged, a reopened list counts them again. Changes made through the lists returned by Get are not logged.
+//AddWithTTL is logged as Add and the expiry as RemoveAt. The time-to-live itself is not logged, a reopened list keeps
+//the element without one. Expired elements are removed by the next change of the durable list or by its janitor.
+type DurableTagList[T any] interface {
+	GuardedTagList[T]
+
+	//Writes a snapshot and truncates the log.
+	Compact() error
+	//Flushes the log to disk.
+	Sync() error
+	//Returns the first error of the log. Mutations are not applied after an error.
+	Err() error
+	//Stops the background goroutines, flushes and closes the log.
+	Close() error
+}
+
+type durableTagList[T any] struct {
+	*guardedTagList[T]
+
+	walLocker  sync.Mutex
+	path       string
+	codec      Codec[T]
+	options    durableOptions
+	file       *os.File
+	generation uint64
+	err        error
+	dirty      bool
+
+	//Ids of the logged elements.
+	ids    map[*T]uint64
+	nextID uint64
+
+	//Elements of AddWithTTL. The durable list expires them itself, so that the expiry is logged before it is applied.
+	ttls   map[cacheKey[T]]*durableTTL[T]
+	expiry *priorityList[durableTTL[T]]
+	//Expired but not yet passed to the OnExpire func.
+	expired []*durableTTL[T]
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+	//Last error of a background compaction, returned once by Compact or Close.
+	backgroundErr error
+}
+
+//Element of AddWithTTL of a durable list.
+type durableTTL[T any] struct {
+	cacheKey[T]
+	ttl     time.Duration
+	expires time.Time
+}
+
+//Opens the durable GuardedTagList stored at path, replaying the snapshot and the log. A nil codec uses GobCodec[T].
+func Open[T any](path string, codec Codec[T], options ...DurableOption) (DurableTagList[T], error) {
+	if codec == nil {
+		codec = GobCodec[T]{}
+	}
+
+	d := &durableTagList[T]{
+		guardedTagList: &guardedTagList[T]{
+			mapList: make(map[string]GuardedPointerList[T]),
+		},
+		path:  path,
+		codec: codec,
+		ids:   make(map[*T]uint64),
+		stop:  make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(&d.options)
+	}
+
+	objects, err := d.loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644); err != nil {
+		return nil, err
+	}
+
+	if err := d.replay(objects); err != nil {
+		d.file.Close()
+		return nil, err
+	}
+
+	for id, item := range objects {
+		d.ids[item] = id
+
+		if id > d.nextID {
+			d.nextID = id
+		}
+	}
+
+	d.startBackground()
+
+	return d, nil
+}
+
+//Adds a tag with the specified key and value to the list. The elements removed to make room are logged as RemoveAt,
+//nothing is removed or logged if a Unique index of the tag rejects the element.
+func (d *durableTagList[T]) Add(key string, value *T) (*T, error) {
+	d.lock()
+	defer d.unlock()
+
+	evicted, _, err := d.makeRoom(key, value, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.log(d.encode(walOpAdd, key, -1, value, true)); err != nil {
+		return nil, err
+	}
+
+	d.guardedTagList.Add(key, value)
+
+	return evicted, nil
+}
+
+//Inserts an element into the GuardedTagList at the specified index. The elements removed to make room are logged as RemoveAt,
+//nothing is removed or logged if a Unique index of the tag rejects the element.
+func (d *durableTagList[T]) Insert(index int, key string, value *T) (*T, error) {
+	d.lock()
+	defer d.unlock()
+
+	if count := countOf[T](d.guardedTagList.Get(key)); index < 0 || index > count {
+		return nil, GetErrorf(IndexOutOfRange, index, count)
+	}
+
+	evicted, index, err := d.makeRoom(key, value, index)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.log(d.encode(walOpInsert, key, index, value, true)); err != nil {
+		return nil, err
+	}
+
+	_, err = d.guardedTagList.Insert(index, key, value)
+
+	return evicted, err
+}
+
+//Removes the first occurrence of a specific object from the GuardedTagList.
+func (d *durableTagList[T]) Remove(key string, value *T) bool {
+	d.lock()
+	defer d.unlock()
+
+	if index := d.indexOf(key, value); index >= 0 {
+		return d.removeAt(key, index)
+	}
+
+	return false
+}
+
+//Removes the element at the specified index of the GuardedTagList.
+func (d *durableTagList[T]) RemoveAt(key string, index int) bool {
+	d.lock()
+	defer d.unlock()
+
+	list := d.guardedTagList.Get(key)
+	if list == nil || index < 0 || index >= list.Count() {
+		return false
+	}
+
+	return d.removeAt(key, index)
+}
+
+//Removes target list from the GuardedTagList.
+func (d *durableTagList[T]) ClearList(key string) {
+	d.lock()
+	defer d.unlock()
+
+	if d.log(d.encode(walOpClearList, key, -1, nil, false)) == nil {
+		d.guardedTagList.ClearList(key)
+		d.untrackTag(key)
+	}
+}
+
+//Removes all elements from the GuardedTagList.
+func (d *durableTagList[T]) Clear() {
+	d.lock()
+	defer d.unlock()
+
+	if d.log(d.encode(walOpClear, "", -1, nil, false)) == nil {
+		d.guardedTagList.Clear()
+		d.untrackAll()
+	}
+}
+
+//Adds a tag with the specified key and value to the list. Returns false if the log could not be locked within timeout,
+//or false and a KeyLimit error if the tag is at its limit with LimitReject, or the error of the log.
+func (d *durableTagList[T]) TryAdd(key string, value *T, timeout time.Duration) (bool, error) {
+	if !tryLock(&d.walLocker, timeout) {
+		return false, nil
+	}
+	defer d.unlock()
+
+	d.expire()
+
+	if _, _, err := d.makeRoom(key, value, -1); err != nil {
+		return false, err
+	}
+
+	//The element is logged already, so it is added even if the list itself is busy.
+	if err := d.log(d.encode(walOpAdd, key, -1, value, true)); err != nil {
+		return false, err
+	}
+
+	d.guardedTagList.Add(key, value)
+
+	return true, nil
+}
+
+//Adds a tag with the specified key and value that is removed once ttl passed without a Touch, logged as Add.
+//The expiry is logged as RemoveAt. Returns a KeyLimit error if the tag is at its limit with LimitReject.
+func (d *durableTagList[T]) AddWithTTL(key string, value *T, ttl time.Duration) error {
+	d.lock()
+	defer d.unlock()
+
+	if _, _, err := d.makeRoom(key, value, -1); err != nil {
+		return err
+	}
+
+	if err := d.log(d.encode(walOpAdd, key, -1, value, true)); err != nil {
+		return err
+	}
+
+	d.guardedTagList.Add(key, value)
+	d.schedule(key, value, ttl)
+
+	return nil
+}
+
+//Restarts the time-to-live of an element added by AddWithTTL. Returns false if it has none.
+func (d *durableTagList[T]) Touch(key string, value *T) bool {
+	d.lock()
+	defer d.unlock()
+
+	entry := d.ttls[cacheKey[T]{key: key, item: value}]
+	if entry == nil {
+		return false
+	}
+
+	entry.expires = d.guardedTagList.clock().Now().Add(entry.ttl)
+	d.expiry.Update(entry)
+
+	return true
+}
+
+//Starts a goroutine removing the expired elements of AddWithTTL every interval, until Close.
+func (d *durableTagList[T]) StartJanitor(interval time.Duration) {
+	l := d.guardedTagList
+
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	if l.stop != nil {
+		return
+	}
+
+	l.stop = make(chan struct{})
+	go runJanitor(l.clock(), interval, l.stop, func() {
+		d.lock()
+		d.unlock()
+	})
+}
+
+//Adds a tag with the specified key and value if the tag is still at version, logged as Add.
+//A tag at its limit returns a KeyLimit error whatever its LimitPolicy.
+func (d *durableTagList[T]) AddIfVersion(key string, version uint64, value *T) error {
+	d.lock()
+	defer d.unlock()
+
+	if err := d.checkVersion(key, version, true); err != nil {
+		return err
+	} else if err := d.guardedTagList.rejects(key, value); err != nil {
+		return err
+	}
+
+	if err := d.log(d.encode(walOpAdd, key, -1, value, true)); err != nil {
+		return err
+	}
+
+	return d.guardedTagList.AddIfVersion(key, version, value)
+}
+
+//Replaces the element of the tag at the specified index if the tag is still at version, logged as RemoveAt and Insert.
+func (d *durableTagList[T]) SetIfVersion(key string, version uint64, index int, value *T) error {
+	d.lock()
+	defer d.unlock()
+
+	if err := d.checkVersion(key, version, false); err != nil {
+		return err
+	}
+
+	if count := countOf[T](d.guardedTagList.Get(key)); index < 0 || index >= count {
+		return GetErrorf(IndexOutOfRange, index, count)
+	}
+
+	if err := d.log(d.encode(walOpRemoveAt, key, index, nil, false)); err != nil {
+		return err
+	}
+
+	if err := d.log(d.encode(walOpInsert, key, index, value, true)); err != nil {
+		return err
+	}
+
+	d.untrack(key, d.guardedTagList.Get(key).Get(index))
+
+	return d.guardedTagList.SetIfVersion(key, version, index, value)
+}
+
+//Replaces the elements of the tag if the tag is still at version, logged as ClearList and an Add for every element.
+func (d *durableTagList[T]) ReplaceIfVersion(key string, version uint64, items []*T) error {
+	d.lock()
+	defer d.unlock()
+
+	if err := d.checkVersion(key, version, false); err != nil {
+		return err
+	}
+
+	if err := d.log(d.encode(walOpClearList, key, -1, nil, false)); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := d.log(d.encode(walOpAdd, key, -1, item, true)); err != nil {
+			return err
+		}
+	}
+
+	d.untrackTag(key)
+
+	return d.guardedTagList.ReplaceIfVersion(key, version, items)
+}
+
+//Loop. removeCurItem is logged as RemoveAt.
+func (d *durableTagList[T]) Foreach(f ForeachTagListFunc[T]) {
+	d.lock()
+	defer d.unlock()
+
+	d.guardedTagList.Foreach(d.loggedForeach(f))
+}
+
+//Loop. removeCurItem is logged as RemoveAt once the walk completed. Returns ctx.Err() if the context is done, nothing is removed.
+func (d *durableTagList[T]) ForeachCtx(ctx context.Context, f ForeachTagListFunc[T]) error {
+	d.lock()
+	defer d.unlock()
+
+	d.guardedTagList.lock()
+	defer d.guardedTagList.unlock()
+
+	removals, err := foreachTagsCtx[T](ctx, d.guardedTagList.mapList, f)
+	if err != nil {
+		return err
+	}
+
+	//The log replays the removals in order, so each index is moved by the removals before it in the same tag.
+	shift := make(map[PointerList[T]]int)
+
+	for i, removal := range removals {
+		if err := d.log(d.encode(walOpRemoveAt, removal.key, removal.index-shift[removal.list], nil, false)); err != nil {
+			applyTagRemovals(removals[:i])
+			return err
+		}
+
+		d.untrack(removal.key, removal.list.Get(removal.index))
+		shift[removal.list]++
+	}
+
+	applyTagRemovals(removals)
+
+	return nil
+}
+
+//Replaces the tags with a snapshot written by WriteTo and compacts the log.
+func (d *durableTagList[T]) ReadFrom(r io.Reader) (int64, error) {
+	d.lock()
+	defer d.unlock()
+
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	n, err := d.guardedTagList.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+
+	d.untrackAll()
+
+	return n, d.compact()
+}
+
+//Replaces the tags with a decoded JSON object and compacts the log.
+func (d *durableTagList[T]) UnmarshalJSON(data []byte) error {
+	d.lock()
+	defer d.unlock()
+
+	if d.err != nil {
+		return d.err
+	}
+
+	if err := d.guardedTagList.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	d.untrackAll()
+
+	return d.compact()
+}
+
+//Adds the rows of a CSV with a leading "tag" column, every row is logged as Add. The limits of the tags are checked
+//before the first row is added, so nothing is added if a row does not fit.
+func (d *durableTagList[T]) ImportCSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, ',', build, d.guardedTagList.fits, d.Add)
+}
+
+//Same as ImportCSV, separated by tabs.
+func (d *durableTagList[T]) ImportTSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, '\t', build, d.guardedTagList.fits, d.Add)
+}
+
+//Writes a snapshot and truncates the log.
+//Returns the error of the last background compaction if this one succeeded.
+func (d *durableTagList[T]) Compact() error {
+	d.lock()
+	defer d.unlock()
+
+	if err := d.compact(); err != nil {
+		return err
+	}
+
+	return d.takeBackgroundErr()
+}
+
+//Flushes the log to disk.
+func (d *durableTagList[T]) Sync() error {
+	d.walLocker.Lock()
+	defer d.walLocker.Unlock()
+
+	return d.sync()
+}
+
+//Returns the first error of the log. Mutations are not applied after an error.
+func (d *durableTagList[T]) Err() error {
+	d.walLocker.Lock()
+	defer d.walLocker.Unlock()
+
+	return d.err
+}
+
+//Stops the background goroutines, flushes and closes the log.
+func (d *durableTagList[T]) Close() error {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+	})
+	d.wg.Wait()
+	d.guardedTagList.Close()
+
+	d.walLocker.Lock()
+	defer d.walLocker.Unlock()
+
+	if d.file == nil {
+		return nil
+	}
+
+	err := d.file.Sync()
+
+	if closeErr := d.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	d.file = nil
+
+	if d.err == nil {
+		d.err = GetError(DurableListClosed)
+	}
+
+	if err == nil {
+		err = d.takeBackgroundErr()
+	}
+
+	return err
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func (d *durableTagList[T]) removeAt(key string, index int) bool {
+	if d.log(d.encode(walOpRemoveAt, key, index, nil, false)) != nil {
+		return false
+	}
+
+	d.untrack(key, d.guardedTagList.Get(key).Get(index))
+
+	return d.guardedTagList.RemoveAt(key, index)
+}
+
+//Locks the log and removes the expired elements of AddWithTTL.
+func (d *durableTagList[T]) lock() {
+	d.walLocker.Lock()
+	d.expire()
+}
+
+//Unlocks the log, then passes the expired elements to the OnExpire func.
+func (d *durableTagList[T]) unlock() {
+	expired := d.expired
+	d.expired = nil
+	d.walLocker.Unlock()
+
+	if len(expired) == 0 {
+		return
+	}
+
+	d.guardedTagList.locker.Lock()
+	f := d.guardedTagList.onExpire
+	d.guardedTagList.locker.Unlock()
+
+	if f == nil {
+		return
+	}
+
+	for _, entry := range expired {
+		f(entry.key, entry.item)
+	}
+}
+
+//Removes the expired elements of AddWithTTL, logged as RemoveAt. The log is locked.
+func (d *durableTagList[T]) expire() {
+	if d.expiry == nil {
+		return
+	}
+
+	now := d.guardedTagList.clock().Now()
+
+	for next := d.expiry.Peek(); next != nil && !next.expires.After(now); next = d.expiry.Peek() {
+		index := d.indexOf(next.key, next.item)
+		if index < 0 {
+			d.untrack(next.key, next.item)
+			continue
+		}
+
+		//A log error leaves the element in the list, it expires with the next change that could be logged.
+		if !d.removeAt(next.key, index) {
+			return
+		}
+
+		d.expired = append(d.expired, next)
+	}
+}
+
+func (d *durableTagList[T]) schedule(key string, value *T, ttl time.Duration) {
+	if d.expiry == nil {
+		d.ttls = make(map[cacheKey[T]]*durableTTL[T])
+		d.expiry = &priorityList[durableTTL[T]]{
+			heap:    make([]*durableTTL[T], 0),
+			indexOf: make(map[*durableTTL[T]]int),
+			f: func(left *durableTTL[T], right *durableTTL[T]) bool {
+				return left.expires.After(right.expires)
+			},
+		}
+	}
+
+	k := cacheKey[T]{key: key, item: value}
+	entry := d.ttls[k]
+
+	if entry == nil {
+		entry = &durableTTL[T]{cacheKey: k}
+		d.ttls[k] = entry
+	}
+
+	entry.ttl = ttl
+	entry.expires = d.guardedTagList.clock().Now().Add(ttl)
+	d.expiry.Push(entry)
+}
+
+//The element left the tag, it no longer expires.
+func (d *durableTagList[T]) untrack(key string, value *T) {
+	k := cacheKey[T]{key: key, item: value}
+
+	if entry := d.ttls[k]; entry != nil {
+		d.expiry.RemoveItem(entry)
+		delete(d.ttls, k)
+	}
+}
+
+func (d *durableTagList[T]) untrackTag(key string) {
+	for k := range d.ttls {
+		if k.key == key {
+			d.untrack(k.key, k.item)
+		}
+	}
+}
+
+func (d *durableTagList[T]) untrackAll() {
+	for k := range d.ttls {
+		d.untrack(k.key, k.item)
+	}
+}
+
+//Returns the error of the last background compaction once.
+func (d *durableTagList[T]) takeBackgroundErr() error {
+	err := d.backgroundErr
+	d.backgroundErr = nil
+
+	return err
+}
+
+//Index of the first occurrence of the element in the tag, or -1.
+func (d *durableTagList[T]) indexOf(key string, value *T) int {
+	list := d.guardedTagList.Get(key)
+	if list == nil {
+		return -1
+	}
+
+	return sliceFindIndex(snapshotOf[T](list), func(index int, current *T) bool {
+		return current == value
+	})
+}
+
+//Removes the elements that make room for value in the tag, logged as RemoveAt, once the indexes of the tag accepted it.
+//at is moved with them. Returns the first removed element.
+func (d *durableTagList[T]) makeRoom(key string, value *T, at int) (*T, int, error) {
+	if err := d.guardedTagList.rejects(key, value); err != nil {
+		return nil, at, err
+	}
+
+	var evicted *T
+
+	for {
+		index, err := d.guardedTagList.roomIndex(key)
+		if index < 0 {
+			return evicted, at, err
+		}
+
+		if evicted == nil {
+			evicted = d.guardedTagList.Get(key).Get(index)
+		}
+
+		if !d.removeAt(key, index) {
+			return nil, at, d.err
+		}
+
+		if index < at {
+			at--
+		}
+	}
+}
+
+func (d *durableTagList[T]) loggedForeach(f ForeachTagListFunc[T]) ForeachTagListFunc[T] {
+	return func(key string, index int, current *T, removeCurItem func()) bool {
+		removeItem := func() {
+			if d.log(d.encode(walOpRemoveAt, key, index, nil, false)) == nil {
+				d.untrack(key, current)
+				removeCurItem()
+			}
+		}
+
+		return f(key, index, current, removeItem)
+	}
+}
+
+//Builds the payload of a record. A nil payload means the element could not be encoded, the error is kept in d.err.
+func (d *durableTagList[T]) encode(op byte, key string, index int, item *T, withItem bool) []byte {
+	payload := []byte{op}
+	payload = appendUvarint(payload, uint64(len(key)))
+	payload = append(payload, key...)
+
+	if index >= 0 {
+		payload = appendUvarint(payload, uint64(index))
+	}
+
+	if !withItem {
+		return payload
+	}
+
+	if item == nil {
+		return appendUvarint(payload, 0)
+	}
+
+	data, err := d.codec.Marshal(item)
+	if err != nil {
+		if d.err == nil {
+			d.err = err
+		}
+		return nil
+	}
+
+	id, ok := d.ids[item]
+	if !ok {
+		d.nextID++
+		id = d.nextID
+		d.ids[item] = id
+	}
+
+	payload = appendUvarint(payload, id)
+	payload = appendUvarint(payload, uint64(len(data)))
+
+	return append(payload, data...)
+}
+
+//Appends a record to the log.
+func (d *durableTagList[T]) log(payload []byte) error {
+	if d.err != nil || payload == nil {
+		return d.err
+	}
+
+	frame := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(payload))
+	frame = append(frame, payload...)
+
+	if _, err := d.file.Write(frame); err != nil {
+		d.err = err
+		return err
+	}
+
+	switch d.options.sync {
+	case SyncAlways:
+		if err := d.file.Sync(); err != nil {
+			d.err = err
+			return err
+		}
+	case SyncInterval:
+		d.dirty = true
+	}
+
+	return nil
+}
+
+func (d *durableTagList[T]) sync() error {
+	if d.file == nil {
+		return d.err
+	}
+
+	d.dirty = false
+
+	return d.file.Sync()
+}
+
+func (d *durableTagList[T]) compact() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	generation := d.generation + 1
+	tmpPath := d.path + ".snap.tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	objects, err := d.writeSnapshot(file, generation)
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, d.path+".snap"); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	//From here on the snapshot is the newer state, a log that can not be restarted would be replayed twice.
+	if err := d.resetLog(generation); err != nil {
+		d.err = err
+		return err
+	}
+
+	d.generation = generation
+	d.ids = objects.ids
+	d.nextID = uint64(len(objects.items))
+
+	return nil
+}
+
+func (d *durableTagList[T]) writeSnapshot(file *os.File, generation uint64) (*snapshotObjects[T], error) {
+	w := bufio.NewWriter(file)
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint64(header, generation)
+
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	objects, _, err := writeTagSnapshot(w, d.guardedTagList.ToArrayMap(), d.codec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return objects, file.Sync()
+}
+
+//Truncates the log and writes the header of the generation.
+func (d *durableTagList[T]) resetLog(generation uint64) error {
+	if err := d.file.Truncate(0); err != nil {
+		return err
+	}
+
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	header := make([]byte, len(walMagic)+9)
+	copy(header, walMagic)
+	header[len(walMagic)] = walVersion
+	binary.BigEndian.PutUint64(header[len(walMagic)+1:], generation)
+
+	if _, err := d.file.Write(header); err != nil {
+		return err
+	}
+
+	return d.file.Sync()
+}
+
+//Loads the snapshot file, if there is one. Returns the objects by id.
+func (d *durableTagList[T]) loadSnapshot() (map[uint64]*T, error) {
+	objects := make(map[uint64]*T)
+
+	file, err := os.Open(d.path + ".snap")
+	if errors.Is(err, os.ErrNotExist) {
+		return objects, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	header := make([]byte, 8)
+
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, snapshotReadError(err)
+	}
+
+	items, snapshotObjects, _, err := readTagSnapshot(r, d.codec)
+	if err != nil {
+		return nil, err
+	}
+
+	d.generation = binary.BigEndian.Uint64(header)
+	d.guardedTagList.replaceMap(items)
+
+	for i := 0; i < len(snapshotObjects); i++ {
+		objects[uint64(i+1)] = snapshotObjects[i]
+	}
+
+	return objects, nil
+}
+
+//Applies the records of the log. A torn or corrupted record and everything after it is cut off.
+func (d *durableTagList[T]) replay(objects map[uint64]*T) error {
+	r := bufio.NewReader(d.file)
+	header := make([]byte, len(walMagic)+9)
+
+	if n, err := io.ReadFull(r, header); err != nil {
+		if n > len(walMagic) {
+			n = len(walMagic)
+		}
+
+		if string(header[:n]) != walMagic[:n] {
+			return GetError(InvalidLog)
+		}
+
+		//Empty log, or the header itself was torn.
+		return d.resetLog(d.generation)
+	}
+
+	if string(header[:len(walMagic)]) != walMagic {
+		return GetError(InvalidLog)
+	}
+
+	if header[len(walMagic)] != walVersion {
+		return GetError(UnsupportedLogVersion)
+	}
+
+	generation := binary.BigEndian.Uint64(header[len(walMagic)+1:])
+
+	if generation < d.generation {
+		//Compacted into the snapshot already.
+		return d.resetLog(d.generation)
+	} else if generation > d.generation {
+		return GetError(InvalidLog)
+	}
+
+	info, err := d.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	offset := int64(len(header))
+	frame := make([]byte, 8)
+
+	for {
+		if _, err := io.ReadFull(r, frame); err != nil {
+			break
+		}
+
+		length := int64(binary.BigEndian.Uint32(frame[0:4]))
+
+		//A corrupted length can not be longer than the rest of the file.
+		if length > info.Size()-offset-int64(len(frame)) {
+			break
+		}
+
+		payload := make([]byte, length)
+
+		if _, err := io.ReadFull(r, payload); err != nil {
+			break
+		}
+
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(frame[4:8]) {
+			break
+		}
+
+		if err := d.apply(payload, objects); err != nil {
+			return err
+		}
+
+		offset += int64(len(frame) + len(payload))
+	}
+
+	if err := d.file.Truncate(offset); err != nil {
+		return err
+	}
+
+	_, err = d.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (d *durableTagList[T]) apply(payload []byte, objects map[uint64]*T) error {
+	r := bytes.NewReader(payload)
+
+	op, err := r.ReadByte()
+	if err != nil {
+		return GetError(InvalidLog)
+	}
+
+	key, err := readLogBytes(r)
+	if err != nil {
+		return err
+	}
+
+	index := uint64(0)
+
+	if op == walOpInsert || op == walOpRemoveAt {
+		if index, err = binary.ReadUvarint(r); err != nil {
+			return GetError(InvalidLog)
+		}
+	}
+
+	var item *T
+
+	if op == walOpAdd || op == walOpInsert {
+		if item, err = d.readLogItem(r, objects); err != nil {
+			return err
+		}
+	}
+
+	switch op {
+	case walOpAdd:
+		d.guardedTagList.Add(string(key), item)
+	case walOpInsert:
+		d.guardedTagList.Insert(int(index), string(key), item)
+	case walOpRemoveAt:
+		d.guardedTagList.RemoveAt(string(key), int(index))
+	case walOpClearList:
+		d.guardedTagList.ClearList(string(key))
+	case walOpClear:
+		d.guardedTagList.Clear()
+	default:
+		return GetError(InvalidLog)
+	}
+
+	return nil
+}
+
+//An element with a known id is updated in place, so that it stays the same pointer.
+func (d *durableTagList[T]) readLogItem(r *bytes.Reader, objects map[uint64]*T) (*T, error) {
+	id, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, GetError(InvalidLog)
+	} else if id == 0 {
+		return nil, nil
+	}
+
+	data, err := readLogBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := d.codec.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if current, ok := objects[id]; ok {
+		*current = *item
+		return current, nil
+	}
+
+	objects[id] = item
+
+	return item, nil
+}
+
+func readLogBytes(r *bytes.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil || length > uint64(r.Len()) {
+		return nil, GetError(InvalidLog)
+	}
+
+	data := make([]byte, length)
+	r.Read(data)
+
+	return data, nil
+}
+
+func (d *durableTagList[T]) startBackground() {
+	if d.options.sync == SyncInterval && d.options.syncInterval > 0 {
+		d.every(d.options.syncInterval, func() {
+			if d.dirty {
+				d.sync()
+			}
+		})
+	}
+
+	if d.options.compactInterval > 0 {
+		d.every(d.options.compactInterval, func() {
+			if err := d.compact(); err != nil {
+				d.backgroundErr = err
+			}
+		})
+	}
+}
+
+//Calls f under the log lock every interval until Close.
+func (d *durableTagList[T]) every(interval time.Duration, f func()) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.walLocker.Lock()
+				f()
+				d.walLocker.Unlock()
+			}
+		}
+	}()
+}
@@ -0,0 +1,290 @@
+package PointerList
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openDurable(t *testing.T, path string) DurableTagList[serialPlayer] {
+	list, err := Open[serialPlayer](path, nil, WithSync(SyncNever))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return list
+}
+
+func TestDurableReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.wal")
+	list := openDurable(t, path)
+
+	shared := &serialPlayer{ID: 1, Health: 100}
+	list.Add("red", shared)
+	list.Add("alive", shared)
+	list.Add("red", &serialPlayer{ID: 2})
+	list.Insert(0, "red", &serialPlayer{ID: 3})
+	list.Add("blue", &serialPlayer{ID: 4})
+	list.Remove("red", shared)
+	list.ClearList("blue")
+
+	shared.Health = 50
+	list.Add("healed", shared)
+
+	if err := list.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := openDurable(t, path)
+	defer restored.Close()
+
+	if counts := restored.MapCount(); counts["red"] != 2 || counts["alive"] != 1 || counts["blue"] != 0 || counts["healed"] != 1 {
+		t.Errorf("replay => %v", counts)
+	}
+
+	if restored.Get("red").Get(0).ID != 3 || restored.Get("alive").Get(0) != restored.Get("healed").Get(0) {
+		t.Errorf("replay => shared pointer is not shared")
+	}
+
+	if restored.Get("alive").Get(0).Health != 50 {
+		t.Errorf("replay => health(%f)", restored.Get("alive").Get(0).Health)
+	}
+}
+
+func TestDurableForeachCtx(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.wal")
+	list := openDurable(t, path)
+
+	for i := 1; i <= 5; i++ {
+		list.Add("red", &serialPlayer{ID: i})
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	removeOdd := func(key string, index int, current *serialPlayer, removeCurItem func()) bool {
+		if current.ID%2 == 1 {
+			removeCurItem()
+		}
+		return true
+	}
+
+	if err := list.ForeachCtx(cancelled, removeOdd); err != context.Canceled || list.Get("red").Count() != 5 {
+		t.Errorf("ForeachCtx => err(%v) count(%d)", err, list.Get("red").Count())
+	}
+
+	if err := list.ForeachCtx(context.Background(), removeOdd); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := list.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := openDurable(t, path)
+	defer restored.Close()
+
+	if red := restored.Get("red"); red.Count() != 2 || red.Get(0).ID != 2 || red.Get(1).ID != 4 {
+		t.Errorf("replay => %v", red.ToArray())
+	}
+}
+
+func TestDurableKeyLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.wal")
+	list := openDurable(t, path)
+	list.SetKeyLimit("red", 2, LimitDropOldest)
+
+	list.Add("red", &serialPlayer{ID: 1})
+	list.Add("red", &serialPlayer{ID: 2})
+	list.Insert(1, "red", &serialPlayer{ID: 3})
+
+	if err := list.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	//The eviction is logged, the restored list has no limit.
+	restored := openDurable(t, path)
+	defer restored.Close()
+
+	if red := restored.Get("red"); red.Count() != 2 || red.Get(0).ID != 3 || red.Get(1).ID != 2 {
+		t.Errorf("replay => %v", red.ToArray())
+	}
+}
+
+func TestDurableVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.wal")
+	list := openDurable(t, path)
+
+	list.AddIfVersion("red", 0, &serialPlayer{ID: 1})
+	list.SetIfVersion("red", list.Version("red"), 0, &serialPlayer{ID: 2})
+
+	if err := list.AddIfVersion("red", 0, &serialPlayer{ID: 3}); err != ErrConflict {
+		t.Errorf("AddIfVersion => %v", err)
+	}
+
+	list.ReplaceIfVersion("blue", 0, []*serialPlayer{{ID: 4}, {ID: 5}})
+
+	if err := list.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := openDurable(t, path)
+	defer restored.Close()
+
+	if red := restored.Get("red"); red.Count() != 1 || red.Get(0).ID != 2 {
+		t.Errorf("replay red => %v", red.ToArray())
+	}
+
+	if blue := restored.Get("blue"); blue.Count() != 2 || blue.Get(1).ID != 5 {
+		t.Errorf("replay blue => %v", blue.ToArray())
+	}
+}
+
+func TestDurableCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.wal")
+	list := openDurable(t, path)
+
+	shared := &serialPlayer{ID: 1}
+	list.Add("red", shared)
+	list.Add("alive", shared)
+
+	staleLog, _ := os.ReadFile(path)
+
+	if err := list.Compact(); err != nil {
+		t.Fatal(err)
+	}
+
+	list.Add("blue", shared)
+	list.Close()
+
+	restored := openDurable(t, path)
+
+	if counts := restored.MapCount(); counts["red"] != 1 || counts["alive"] != 1 || counts["blue"] != 1 {
+		t.Errorf("compact => %v", counts)
+	}
+
+	if restored.Get("red").Get(0) != restored.Get("blue").Get(0) {
+		t.Errorf("compact => pointer logged after the snapshot is not shared")
+	}
+
+	restored.Close()
+
+	//Crash after the snapshot was written but before the log was restarted: the old log must not be replayed twice.
+	os.WriteFile(path, staleLog, 0644)
+
+	restored = openDurable(t, path)
+	defer restored.Close()
+
+	if counts := restored.MapCount(); counts["red"] != 1 || counts["alive"] != 1 || len(counts) != 2 {
+		t.Errorf("stale log => %v", counts)
+	}
+}
+
+func TestDurableTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.wal")
+	list := openDurable(t, path)
+
+	var expired []int
+	list.OnExpire(func(key string, current *serialPlayer) {
+		expired = append(expired, current.ID)
+	})
+
+	list.AddWithTTL("red", &serialPlayer{ID: 1}, time.Hour)
+	list.AddWithTTL("red", &serialPlayer{ID: 2}, time.Nanosecond)
+	list.Add("red", &serialPlayer{ID: 3})
+	time.Sleep(time.Millisecond)
+
+	//The expiry is logged before the RemoveAt, so the index points at the same element after replay.
+	list.RemoveAt("red", 1)
+
+	if fmt.Sprint(expired) != "[2]" || list.Get("red").Count() != 1 {
+		t.Errorf("expiry => expired(%v) count(%d)", expired, list.Get("red").Count())
+	}
+
+	list.Close()
+
+	restored := openDurable(t, path)
+	defer restored.Close()
+
+	if red := restored.Get("red"); red.Count() != 1 || red.Get(0).ID != 1 {
+		t.Errorf("replay => count(%d)", restored.TotalCount())
+	}
+
+	//The time-to-live is not logged.
+	if restored.Touch("red", restored.Get("red").Get(0)) {
+		t.Errorf("replay => element kept its time-to-live")
+	}
+}
+
+func TestDurableBackgroundCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.wal")
+
+	//The snapshot can not be created.
+	os.Mkdir(path+".snap.tmp", 0755)
+
+	list, err := Open[serialPlayer](path, nil, WithSync(SyncNever), WithCompactInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list.Add("red", &serialPlayer{ID: 1})
+	time.Sleep(20 * time.Millisecond)
+
+	if err := list.Close(); err == nil {
+		t.Errorf("Close => background compaction error dropped")
+	}
+}
+
+func TestDurableTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "players.wal")
+	list := openDurable(t, path)
+
+	for i := 0; i < 10; i++ {
+		list.Add("red", &serialPlayer{ID: i})
+	}
+
+	list.Close()
+
+	data, _ := os.ReadFile(path)
+
+	//The last record is cut in the middle.
+	os.WriteFile(path, data[:len(data)-5], 0644)
+
+	restored := openDurable(t, path)
+
+	if restored.TotalCount() != 9 {
+		t.Errorf("torn record => count(%d)", restored.TotalCount())
+	}
+
+	restored.Add("red", &serialPlayer{ID: 100})
+	restored.Close()
+
+	//The torn tail is cut off, so the record written after the recovery is replayed as well.
+	restored = openDurable(t, path)
+
+	if restored.TotalCount() != 10 || restored.Get("red").Get(9).ID != 100 {
+		t.Errorf("after recovery => count(%d)", restored.TotalCount())
+	}
+
+	restored.Close()
+
+	//Garbage after the last record.
+	data, _ = os.ReadFile(path)
+	os.WriteFile(path, append(data, 0, 0, 0, 9, 1, 2, 3, 4, 5), 0644)
+
+	restored = openDurable(t, path)
+	defer restored.Close()
+
+	if restored.TotalCount() != 10 {
+		t.Errorf("garbage => count(%d)", restored.TotalCount())
+	}
+
+	os.WriteFile(path+".bad", []byte("not a log"), 0644)
+
+	if _, err := Open[serialPlayer](path+".bad", nil); GetErrorType(err) != InvalidLog {
+		t.Errorf("invalid log => err(%v)", err)
+	}
+}
@@ -0,0 +1,183 @@
+package PointerList
+
+/////////////////////////////////
+//           Equality          //
+/////////////////////////////////
+
+//Option of NewPointerList, NewGuardedPointerList, NewPointerSet, NewGuardedPointerSet, NewTagList and NewGuardedTagList.
+type ListOption[T any] func(l *pointerList[T])
+
+//Compares elements with f instead of pointer identity in Contains, Remove, IndexOf and the set operations.
+func WithEquality[T any](f EqualPointerFunc[T]) ListOption[T] {
+	return func(l *pointerList[T]) {
+		l.equal = f
+		l.keyOf = nil
+	}
+}
+
+//Compares elements by the key returned by f instead of pointer identity in Contains, Remove, IndexOf and the set operations.
+//The keys are kept in a hash index, call Reindex after a change of an element changes its key.
+func WithKey[T any, K comparable](f KeyPointerFunc[T, K]) ListOption[T] {
+	return func(l *pointerList[T]) {
+		l.equal = nil
+		l.keyOf = func(current *T) any {
+			return f(current)
+		}
+	}
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func newPointerList[T any](list []*T, base BASE, options []ListOption[T]) *pointerList[T] {
+	l := &pointerList[T]{
+		list: list,
+		BASE: base,
+	}
+
+	for _, option := range options {
+		option(l)
+	}
+
+	if l.keyOf != nil {
+		l.keyIndex = newListIndex("", l.keyOf, false)
+		l.keyIndex.typed = true
+
+		for i := 0; i < len(l.list); i++ {
+			l.keyIndex.add(l.list[i])
+		}
+	}
+
+	if l.history != nil {
+		l.history.owner = l
+		l.addHook(l.history)
+	}
+
+	return l
+}
+
+//Empty unguarded set comparing elements the same way as the list.
+func (l *pointerList[T]) newSet() *pointerList[T] {
+	set := newPointerList[T](make([]*T, 0), nil, []ListOption[T]{func(set *pointerList[T]) {
+		set.equal = l.equal
+		set.keyOf = l.keyOf
+	}})
+	set.positions = newSetPositions[T]()
+
+	return set
+}
+
+//Reports whether elements are compared by value instead of pointer identity.
+func (l *pointerList[T]) byValue() bool {
+	return l.equal != nil || l.keyOf != nil
+}
+
+//Nil is only equal to nil.
+func (l *pointerList[T]) same(left *T, right *T) bool {
+	if left == nil || right == nil {
+		return left == right
+	} else if l.keyOf != nil {
+		return l.keyOf(left) == l.keyOf(right)
+	} else if l.equal != nil {
+		return l.equal(left, right)
+	}
+
+	return left == right
+}
+
+//Determines whether an element equal to the target is in the list.
+func (l *pointerList[T]) has(targetItem *T) bool {
+	if l.keyIndex != nil && targetItem != nil {
+		return len(l.keyIndex.entries[l.keyOf(targetItem)]) > 0
+	} else if l.positions != nil && !l.byValue() {
+		return l.positions.contains(targetItem)
+	}
+
+	return l.find(targetItem) >= 0
+}
+
+//Returns the index of the first element equal to the target, or -1.
+func (l *pointerList[T]) find(targetItem *T) int {
+	if l.positions != nil && !l.byValue() {
+		return l.positions.indexOf(targetItem, l.list)
+	}
+
+	if l.keyIndex != nil && targetItem != nil {
+		candidates := l.keyIndex.entries[l.keyOf(targetItem)]
+
+		if len(candidates) == 0 {
+			return -1
+		} else if l.positions != nil && len(candidates) == 1 {
+			return l.positions.indexOf(candidates[0], l.list)
+		}
+	}
+
+	for i := 0; i < len(l.list); i++ {
+		if l.same(l.list[i], targetItem) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+//Keeps the first of the equal elements.
+func (l *pointerList[T]) distinct(items []*T) []*T {
+	result := l.newSet()
+
+	for _, item := range items {
+		if !result.has(item) {
+			result.list = append(result.list, item)
+			result.indexAdd(item, len(result.list)-1)
+		}
+	}
+
+	return result.list
+}
+
+//Returns a func that determines whether an element equal to its argument is in items.
+func (l *pointerList[T]) matcher(items []*T) func(item *T) bool {
+	if !l.byValue() {
+		members := make(map[*T]bool, len(items))
+
+		for _, item := range items {
+			members[item] = true
+		}
+
+		return func(item *T) bool {
+			return members[item]
+		}
+	}
+
+	if l.keyOf != nil {
+		members := make(map[any]bool, len(items))
+		hasNil := false
+
+		for _, item := range items {
+			if item == nil {
+				hasNil = true
+			} else {
+				members[l.keyOf(item)] = true
+			}
+		}
+
+		return func(item *T) bool {
+			if item == nil {
+				return hasNil
+			}
+
+			return members[l.keyOf(item)]
+		}
+	}
+
+	return func(item *T) bool {
+		for _, current := range items {
+			if l.same(current, item) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
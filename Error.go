@@ -11,10 +11,47 @@ type Error int
 
 const (
 	IndexOutOfRange Error = iota
+	WorkerPanic
+	UnexpectedToken
+	InvalidSnapshot
+	UnsupportedSnapshotVersion
+	SnapshotChecksumMismatch
+	InvalidLog
+	UnsupportedLogVersion
+	DurableListClosed
+	CSVRecord
+	CSVUnsupportedType
+	DuplicateKey
+	UnknownField
+	DuplicateItem
+	KeyLimit
+	UnknownCheckpoint
+	Conflict
 )
 
 var statusText = map[Error]string{
 	IndexOutOfRange: "index out of range [%d] with length %d",
+	WorkerPanic:     "worker panic: %v",
+	UnexpectedToken: "expected %v, found %v",
+
+	InvalidSnapshot:            "invalid snapshot",
+	UnsupportedSnapshotVersion: "unsupported snapshot version",
+	SnapshotChecksumMismatch:   "snapshot checksum mismatch",
+
+	InvalidLog:            "invalid write-ahead log",
+	UnsupportedLogVersion: "unsupported write-ahead log version",
+	DurableListClosed:     "durable list is closed",
+
+	CSVRecord:          "csv record %d: %v",
+	CSVUnsupportedType: "csv: unsupported type %v",
+
+	DuplicateKey:  "duplicate key %v in index %v",
+	UnknownField:  "unknown field %v",
+	DuplicateItem: "item is already in the set",
+	KeyLimit:      "tag %q is at its limit of %d",
+
+	UnknownCheckpoint: "checkpoint %q is not in the history",
+	Conflict:          "version conflict",
 }
 
 func GetError(errType Error) error {
@@ -0,0 +1,149 @@
+package PointerList
+
+/////////////////////////////////
+//          Functional         //
+/////////////////////////////////
+
+//Holds the elements with the same index of two lists.
+type Pair[T any, U any] struct {
+	Left  *T
+	Right *U
+}
+
+//Projects each element of the PointerList[T] into a new PointerList[U].
+func Map[T any, U any](l PointerList[T], f MapPointerFunc[T, U]) PointerList[U] {
+	items := snapshotOf[T](l)
+	retList := make([]*U, len(items))
+
+	for i := 0; i < len(items); i++ {
+		retList[i] = f(items[i])
+	}
+
+	return &pointerList[U]{
+		list: retList,
+	}
+}
+
+//Retrieves all the elements that match the conditions defined by the specified predicate into a new PointerList[T].
+func Filter[T any](l PointerList[T], f PredicatePointerFunc[T]) PointerList[T] {
+	items := snapshotOf[T](l)
+	retList := make([]*T, 0)
+
+	for i := 0; i < len(items); i++ {
+		if f(items[i]) {
+			retList = append(retList, items[i])
+		}
+	}
+
+	return &pointerList[T]{
+		list: retList,
+	}
+}
+
+//Applies an accumulator function over the PointerList[T]. The seed is used as the initial accumulator value.
+func Reduce[T any, A any](l PointerList[T], seed A, f ReducePointerFunc[T, A]) A {
+	items := snapshotOf[T](l)
+	acc := seed
+
+	for i := 0; i < len(items); i++ {
+		acc = f(acc, items[i])
+	}
+
+	return acc
+}
+
+//Splits the PointerList[T] into the elements that match the predicate and the elements that do not.
+func Partition[T any](l PointerList[T], f PredicatePointerFunc[T]) (matched PointerList[T], rest PointerList[T]) {
+	items := snapshotOf[T](l)
+	matchedList := make([]*T, 0)
+	restList := make([]*T, 0)
+
+	for i := 0; i < len(items); i++ {
+		if f(items[i]) {
+			matchedList = append(matchedList, items[i])
+		} else {
+			restList = append(restList, items[i])
+		}
+	}
+
+	return &pointerList[T]{list: matchedList}, &pointerList[T]{list: restList}
+}
+
+//Splits the PointerList[T] into lists of at most size elements. Returns nil if size <= 0.
+func Chunk[T any](l PointerList[T], size int) []PointerList[T] {
+	if size <= 0 {
+		return nil
+	}
+
+	items := snapshotOf[T](l)
+	retList := make([]PointerList[T], 0, (len(items)+size-1)/size)
+
+	for i := 0; i < len(items); i += size {
+		end := i + size
+		if end > len(items) {
+			end = len(items)
+		}
+
+		chunk := make([]*T, end-i)
+		copy(chunk, items[i:end])
+		retList = append(retList, &pointerList[T]{list: chunk})
+	}
+
+	return retList
+}
+
+//Pairs the elements of two lists by index. The result is as long as the shorter list.
+func Zip[T any, U any](left PointerList[T], right PointerList[U]) PointerList[Pair[T, U]] {
+	//Each list is copied under its own lock, so two guarded lists are never locked at the same time.
+	leftItems := snapshotOf[T](left)
+	rightItems := snapshotOf[U](right)
+
+	count := len(leftItems)
+	if len(rightItems) < count {
+		count = len(rightItems)
+	}
+
+	retList := make([]*Pair[T, U], count)
+
+	for i := 0; i < count; i++ {
+		retList[i] = &Pair[T, U]{Left: leftItems[i], Right: rightItems[i]}
+	}
+
+	return &pointerList[Pair[T, U]]{
+		list: retList,
+	}
+}
+
+//Returns the first element for every distinct key, in the original order.
+func Distinct[T any, K comparable](l PointerList[T], f KeyPointerFunc[T, K]) PointerList[T] {
+	items := snapshotOf[T](l)
+	seen := make(map[K]struct{})
+	retList := make([]*T, 0)
+
+	for i := 0; i < len(items); i++ {
+		key := f(items[i])
+
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		retList = append(retList, items[i])
+	}
+
+	return &pointerList[T]{
+		list: retList,
+	}
+}
+
+//Groups the elements of the PointerList[T] by key into a new TagList[T].
+func GroupBy[T any](l PointerList[T], f KeyPointerFunc[T, string]) TagList[T] {
+	items := snapshotOf[T](l)
+	retList := NewTagList[T]()
+
+	for i := 0; i < len(items); i++ {
+		retList.Add(f(items[i]), items[i])
+	}
+
+	return retList
+}
@@ -0,0 +1,250 @@
+package PointerList
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFunctional(t *testing.T) {
+	var list PointerList[int] = NewGuardedPointerList[int]()
+
+	for i := 0; i < 10; i++ {
+		newI := i
+		list.Add(&newI)
+	}
+
+	doubled := Map(list, func(current *int) *string {
+		text := fmt.Sprint(*current * 2)
+		return &text
+	})
+
+	if doubled.Count() != 10 || *doubled.Get(3) != "6" {
+		t.Errorf("Map => count(%d), [3](%s)", doubled.Count(), *doubled.Get(3))
+	}
+
+	even, odd := Partition(list, func(current *int) bool {
+		return *current%2 == 0
+	})
+
+	if even.Count() != 5 || odd.Count() != 5 {
+		t.Errorf("Partition => even(%d) odd(%d)", even.Count(), odd.Count())
+	}
+
+	sum := Reduce(Filter(list, func(current *int) bool { return *current > 5 }), 0, func(acc int, current *int) int {
+		return acc + *current
+	})
+
+	if sum != 6+7+8+9 {
+		t.Errorf("Filter/Reduce => sum(%d)", sum)
+	}
+
+	chunks := Chunk(list, 4)
+
+	if len(chunks) != 3 || chunks[2].Count() != 2 {
+		t.Errorf("Chunk => len(%d)", len(chunks))
+	}
+
+	distinct := Distinct(list, func(current *int) int { return *current % 3 })
+
+	if distinct.Count() != 3 || *distinct.Get(2) != 2 {
+		t.Errorf("Distinct => count(%d)", distinct.Count())
+	}
+
+	pairs := Zip(list, doubled)
+
+	if pairs.Count() != 10 || *pairs.Get(4).Left != 4 || *pairs.Get(4).Right != "8" {
+		t.Errorf("Zip => count(%d)", pairs.Count())
+	}
+
+	groups := GroupBy(list, func(current *int) string {
+		if *current%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	})
+
+	if groups.Count() != 2 || groups.Get("even").Count() != 5 || !groups.Contains("odd", list.Get(1)) {
+		t.Errorf("GroupBy => %v", groups.MapCount())
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	list := NewGuardedPointerList[int]()
+	tags := NewGuardedTagList[int]()
+
+	for _, value := range []int{5, 1, 9, 3, 7, 2} {
+		newValue := value
+		list.Add(&newValue)
+		tags.Add(fmt.Sprint(value%2), &newValue)
+	}
+
+	less := func(left, right *int) bool { return *left < *right }
+
+	if *MinBy[int](list, less) != 1 || *MaxBy[int](list, less) != 9 {
+		t.Errorf("MinBy(%d) MaxBy(%d)", *MinBy[int](list, less), *MaxBy[int](list, less))
+	}
+
+	if sum := SumBy[int](list, func(current *int) int { return *current }); sum != 27 {
+		t.Errorf("SumBy(%d)", sum)
+	}
+
+	if average := AverageBy[int](list, func(current *int) int { return *current }); average != 4.5 {
+		t.Errorf("AverageBy(%f)", average)
+	}
+
+	top := TopK[int](list, 3, less)
+
+	if len(top) != 3 || *top[0] != 9 || *top[1] != 7 || *top[2] != 5 {
+		t.Errorf("TopK => len(%d)", len(top))
+	}
+
+	if buckets := Histogram[int](list, func(current *int) string { return fmt.Sprint(*current > 4) }); buckets["true"] != 3 {
+		t.Errorf("Histogram => %v", buckets)
+	}
+
+	maxTags := MapMaxBy[int](tags, less)
+
+	if *maxTags["0"] != 2 || *maxTags["1"] != 9 {
+		t.Errorf("MapMaxBy => 0(%d) 1(%d)", *maxTags["0"], *maxTags["1"])
+	}
+
+	if sums := MapSumBy[int](tags, func(current *int) int { return *current }); sums["1"] != 25 {
+		t.Errorf("MapSumBy => %v", sums)
+	}
+
+	//A nil element sorts first and can win.
+	nilFirst := func(left, right *int) bool {
+		return left == nil && right != nil || left != nil && right != nil && *left < *right
+	}
+	list.Insert(nil, 2)
+
+	if MinBy[int](list, nilFirst) != nil || *MaxBy[int](list, nilFirst) != 9 {
+		t.Errorf("MinBy with nil => %v", MinBy[int](list, nilFirst))
+	}
+
+	if MaxBy[int](list, func(left, right *int) bool { return nilFirst(right, left) }) != nil {
+		t.Error("MaxBy with nil => not nil")
+	}
+}
+
+type queryPlayer struct {
+	ID     int
+	Team   string
+	Health float64
+}
+
+func TestQuery(t *testing.T) {
+	list := NewPointerList[queryPlayer]()
+	tags := NewTagList[queryPlayer]()
+
+	for i := 0; i < 10; i++ {
+		player := &queryPlayer{ID: i, Team: fmt.Sprint(i % 2), Health: float64(i % 3)}
+		list.Add(player)
+		tags.Add(player.Team, player)
+	}
+
+	result := Query(list).
+		Where(func(current *queryPlayer) bool { return current.ID > 0 }).
+		OrderBy(func(left, right *queryPlayer) bool { return left.Health > right.Health }).
+		ThenBy(func(left, right *queryPlayer) bool { return left.ID < right.ID }).
+		Skip(1).
+		Take(3).
+		ToArray()
+
+	//Health 2 => 2,5,8 | Health 1 => 1,4,7
+	if len(result) != 3 || result[0].ID != 5 || result[1].ID != 8 || result[2].ID != 1 {
+		t.Errorf("Query => len(%d)", len(result))
+	}
+
+	ids := Select(Query(list).Where(func(current *queryPlayer) bool { return current.Team == "1" }), func(current *queryPlayer) *int {
+		return &current.ID
+	}).ToList()
+
+	if ids.Count() != 5 || *ids.Get(0) != 1 {
+		t.Errorf("Select => count(%d)", ids.Count())
+	}
+
+	evaluated := 0
+	first := Query(list).Where(func(current *queryPlayer) bool {
+		evaluated++
+		return current.ID >= 2
+	}).First()
+
+	if first.ID != 2 || evaluated != 3 {
+		t.Errorf("First => id(%d) evaluated(%d)", first.ID, evaluated)
+	}
+
+	count := QueryTags[queryPlayer](tags).Where(func(current *TagItem[queryPlayer]) bool {
+		return current.Key == "0" && current.Value.Health == 0
+	}).Count()
+
+	if count != 2 {
+		t.Errorf("QueryTags => count(%d)", count)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	list := NewGuardedPointerList[int]()
+
+	for i := 0; i < 1000; i++ {
+		newI := i
+		list.Add(&newI)
+	}
+
+	found, err := ParallelFindAll[int](context.Background(), list, 4, func(index int, current *int) bool {
+		return *current%10 == 0
+	})
+
+	if err != nil || len(found) != 100 || *found[1] != 10 || *found[99] != 990 {
+		t.Errorf("ParallelFindAll => len(%d) err(%v)", len(found), err)
+	}
+
+	count, err := ParallelCount[int](context.Background(), list, 4, func(index int, current *int) bool {
+		return *current >= 500
+	})
+
+	if err != nil || count != 500 {
+		t.Errorf("ParallelCount => count(%d) err(%v)", count, err)
+	}
+
+	mapped, err := ParallelMap[int](context.Background(), list, 0, func(current *int) *string {
+		text := fmt.Sprint(*current)
+		return &text
+	})
+
+	if err != nil || mapped.Count() != 1000 || *mapped.Get(999) != "999" {
+		t.Errorf("ParallelMap => err(%v)", err)
+	}
+
+	stopErr := errors.New("stop")
+	err = ParallelForeach[int](context.Background(), list, 4, func(index int, current *int) error {
+		if *current == 300 {
+			return stopErr
+		}
+		return nil
+	})
+
+	if err != stopErr {
+		t.Errorf("ParallelForeach => err(%v)", err)
+	}
+
+	err = ParallelForeach[int](context.Background(), list, 4, func(index int, current *int) error {
+		if *current == 700 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	if err == nil || err.Error() != "worker panic: boom" {
+		t.Errorf("ParallelForeach panic => err(%v)", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ParallelCount[int](ctx, list, 4, func(index int, current *int) bool { return true }); err != context.Canceled {
+		t.Errorf("ParallelCount canceled => err(%v)", err)
+	}
+}
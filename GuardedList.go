@@ -1,5 +1,7 @@
 package PointerList
 
+import "time"
+
 /////////////////////////////////
 //          Guarded List       //
 /////////////////////////////////
@@ -7,12 +9,57 @@ package PointerList
 //List protected by mutex and containing only pointer variables
 type GuardedPointerList[T any] interface {
 	PointerList[T]
+
+	//Adds an object to the end of the GuardedPointerList[T]. Returns false if the lock could not be taken within timeout,
+	//or false and the error of the Unique index that rejected the element.
+	TryAdd(item *T, timeout time.Duration) (bool, error)
+	//Searches for an element that matches the conditions defined by the specified predicate. Returns false if the lock could not be taken within timeout.
+	TryFind(f FindPointerFunc[T], timeout time.Duration) (*T, bool)
+	//Starts a goroutine removing the expired elements every interval, until Close.
+	StartJanitor(interval time.Duration)
+	//Stops the janitor.
+	Close() error
 }
 
-func NewGuardedPointerList[T any]() GuardedPointerList[T] {
+func NewGuardedPointerList[T any](options ...ListOption[T]) GuardedPointerList[T] {
 	baseList := &lockerBase{}
-	return &pointerList[T]{
-		list: make([]*T, 0),
-		BASE: baseList,
+	return newPointerList(make([]*T, 0), baseList, options)
+}
+
+//Adds an object to the end of the GuardedPointerList[T]. Returns false if the lock could not be taken within timeout,
+//or false and the error of the Unique index or PointerSet that rejected the element.
+func (l *pointerList[T]) TryAdd(item *T, timeout time.Duration) (bool, error) {
+	if l.BASE != nil {
+		if !l.tryStart(timeout) {
+			return false, nil
+		}
+		defer l.end()
+	}
+
+	if err := l.indexRejects(item); err != nil {
+		return false, err
+	}
+
+	l.list = append(l.list, item)
+	l.indexAdd(item, len(l.list)-1)
+
+	return true, nil
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns false if the lock could not be taken within timeout.
+func (l *pointerList[T]) TryFind(f FindPointerFunc[T], timeout time.Duration) (*T, bool) {
+	if l.BASE != nil {
+		if !l.tryStart(timeout) {
+			return nil, false
+		}
+		defer l.end()
 	}
+
+	for i := 0; i < len(l.list); i++ {
+		if f(i, l.list[i]) {
+			return l.list[i], true
+		}
+	}
+
+	return nil, true
 }
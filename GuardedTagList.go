@@ -1,16 +1,42 @@
 package PointerList
 
-import "sync"
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
 
 type GuardedTagList[T any] interface {
+	TagSource[T]
+	//Encodes the list as a JSON object keyed by tag.
+	json.Marshaler
+	//Replaces the tags with a decoded JSON object.
+	json.Unmarshaler
+	//Writes a snapshot of the list using GobCodec[T].
+	io.WriterTo
+	//Replaces the tags with a snapshot written by WriteTo.
+	io.ReaderFrom
+	//Writes the list as CSV with a leading "tag" column. Without columns, the fields of T are used.
+	ExportCSV(w io.Writer, columns ...Column[T]) error
+	//Adds the rows of a CSV written by ExportCSV. Without build, the columns are parsed into the fields of T.
+	ImportCSV(r io.Reader, build CSVBuildFunc[T]) error
+	//Same as ExportCSV, separated by tabs.
+	ExportTSV(w io.Writer, columns ...Column[T]) error
+	//Same as ImportCSV, separated by tabs.
+	ImportTSV(r io.Reader, build CSVBuildFunc[T]) error
+
 	ToMap() map[string]GuardedPointerList[T]
 
 	Get(key string) GuardedPointerList[T]
 
 	GetNext(key string) *T
 
-	//Adds a tag with the specified key and value to the list.
-	Add(key string, value *T)
+	//Adds a tag with the specified key and value to the list. A tag at its limit returns a KeyLimit error
+	//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+	//that rejected the element, nothing is removed then.
+	Add(key string, value *T) (*T, error)
 
 	//Removes all elements from the GuardedTagList.
 	Clear()
@@ -21,8 +47,10 @@ type GuardedTagList[T any] interface {
 	//Determines whether a tag is in the GuardedTagList.
 	Contains(key string, value *T) bool
 
-	//Inserts an element into the GuardedTagList at the specified index.
-	Insert(index int, key string, value *T)
+	//Inserts an element into the GuardedTagList at the specified index. A tag at its limit returns a KeyLimit error
+	//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+	//that rejected the element, nothing is removed then.
+	Insert(index int, key string, value *T) (*T, error)
 
 	//Removes the first occurrence of a specific object from the GuardedTagList.
 	Remove(key string, value *T) bool
@@ -39,6 +67,15 @@ type GuardedTagList[T any] interface {
 	//Returns the number of elements in a sequence.
 	MapCount() map[string]int
 
+	//Returns the number of elements and the limit of every tag.
+	MapCountLimit() map[string]KeyCount
+
+	//Limits the number of elements of the tag, n < 1 removes the limit of the tag so the default limit applies.
+	SetKeyLimit(key string, n int, policy LimitPolicy)
+
+	//Limits the number of elements of every tag without a SetKeyLimit, n < 1 removes the limit.
+	SetDefaultLimit(n int, policy LimitPolicy)
+
 	//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]
 	MapCountSelect(f CountSelectTagListFunc[T]) map[string]int
 
@@ -50,18 +87,96 @@ type GuardedTagList[T any] interface {
 
 	GetNextBefore(key string, f BeforeListFunc[T]) *T
 
+	//Adds a tag with the specified key and value to the list. Returns false if the lock could not be taken within timeout,
+	//or false and a KeyLimit error if the tag is at its limit with LimitReject.
+	TryAdd(key string, value *T, timeout time.Duration) (bool, error)
+
+	//Searches for an element that matches the conditions defined by the specified predicate. Returns false if the lock could not be taken within timeout.
+	TryFind(f FindPointerFunc[T], timeout time.Duration) (*T, bool)
+
 	//Loop
 	Foreach(f ForeachTagListFunc[T])
+
+	//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+	MapCountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (map[string]int, error)
+
+	//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+	CountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (int, error)
+
+	//Searches for an element that matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+	FindCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error)
+
+	//Loop. Returns ctx.Err() if the context is done. removeCurItem is applied once the walk completed, so a cancelled
+	//walk removes nothing.
+	ForeachCtx(ctx context.Context, f ForeachTagListFunc[T]) error
+
+	//Adds a tag with the specified key and value that is removed once d passed without a Touch.
+	AddWithTTL(key string, value *T, d time.Duration) error
+
+	//Restarts the time-to-live of an element added by AddWithTTL. Returns false if it has none.
+	Touch(key string, value *T) bool
+
+	//Sets the func called with every expired element, after the GuardedTagList was released, so it may use the GuardedTagList.
+	OnExpire(f ExpireTagFunc[T])
+
+	//Starts a goroutine removing the expired elements of every tag every interval, until Close.
+	StartJanitor(interval time.Duration)
+
+	//Stops the janitor.
+	Close() error
+
+	//Reverts the last step of the tag, see WithHistory. Returns false if there is none or the tag has no history.
+	Undo(key string) bool
+
+	//Applies the last reverted step of the tag again. Returns false if there is none or the tag has no history.
+	Redo(key string) bool
+
+	//Determines whether there is a step of the tag to Undo.
+	CanUndo(key string) bool
+
+	//Determines whether there is a step of the tag to Redo.
+	CanRedo(key string) bool
+
+	//Names the current state of the tag for RevertTo.
+	Checkpoint(key string, name string)
+
+	//Undoes or redoes steps of the tag until it is in the state named by Checkpoint.
+	RevertTo(key string, name string) error
+
+	//Calls f and records the changes it makes to the tag as one step.
+	Batch(key string, f func())
+
+	//Returns a number increased by every change of the tag, 0 for a tag that never had a list.
+	Version(key string) uint64
+
+	//Replaces the elements of the tag if the tag is still at version, else returns ErrConflict.
+	ReplaceIfVersion(key string, version uint64, items []*T) error
+
+	//Replaces the element of the tag at the specified index if the tag is still at version, else returns ErrConflict.
+	SetIfVersion(key string, version uint64, index int, value *T) error
+
+	//Adds a tag with the specified key and value if the tag is still at version, else returns ErrConflict.
+	AddIfVersion(key string, version uint64, value *T) error
 }
 
 type guardedTagList[T any] struct {
-	mapList map[string]GuardedPointerList[T]
-	locker  sync.Mutex
+	mapList      map[string]GuardedPointerList[T]
+	locker       sync.Mutex
+	options      []ListOption[T]
+	onExpire     ExpireTagFunc[T]
+	expiring     expireQueue
+	stop         chan struct{}
+	limits       map[string]keyLimit
+	defaultLimit keyLimit
+	//Versions of the dropped lists, by tag.
+	retired map[string]uint64
 }
 
-func NewGuardedTagList[T any]() GuardedTagList[T] {
+//The options are applied to the list of every tag.
+func NewGuardedTagList[T any](options ...ListOption[T]) GuardedTagList[T] {
 	return &guardedTagList[T]{
 		mapList: make(map[string]GuardedPointerList[T]),
+		options: options,
 	}
 }
 
@@ -80,10 +195,14 @@ func (l *guardedTagList[T]) MapCount() map[string]int {
 }
 
 func (l *guardedTagList[T]) TotalCount() int {
+	l.lock()
+	defer l.unlock()
+
 	count := 0
 
+	//A cleared tag has no list.
 	for _, value := range l.mapList {
-		count += value.Count()
+		count += countOf[T](value)
 	}
 
 	return count
@@ -125,22 +244,59 @@ func (l *guardedTagList[T]) MapCountSelect(f CountSelectTagListFunc[T]) map[stri
 }
 
 func (l *guardedTagList[T]) ToMap() map[string]GuardedPointerList[T] {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	return l.mapList
 }
 
+//Copies the elements of every tag under a single lock of the GuardedTagList.
+func (l *guardedTagList[T]) ToArrayMap() map[string][]*T {
+	l.lock()
+	defer l.unlock()
+
+	items := make(map[string][]*T, len(l.mapList))
+
+	for key, list := range l.mapList {
+		if list != nil {
+			items[key] = snapshotOf[T](list)
+		} else {
+			items[key] = nil
+		}
+	}
+
+	return items
+}
+
+//Replaces every tag with the specified elements under a single lock of the GuardedTagList.
+func (l *guardedTagList[T]) replaceMap(items map[string][]*T) {
+	mapList := make(map[string]GuardedPointerList[T], len(items))
+
+	for key, list := range items {
+		if list != nil {
+			mapList[key] = newPointerList(list, &lockerBase{}, l.options)
+		} else {
+			mapList[key] = nil
+		}
+	}
+
+	l.lock()
+	defer l.unlock()
+
+	l.retireAll()
+	l.mapList = mapList
+}
+
 func (l *guardedTagList[T]) Get(key string) GuardedPointerList[T] {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	return l.mapList[key]
 }
 
 func (l *guardedTagList[T]) GetNext(key string) *T {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	if l.mapList[key] == nil {
 		return nil
@@ -149,38 +305,43 @@ func (l *guardedTagList[T]) GetNext(key string) *T {
 	return l.mapList[key].GetNext()
 }
 
-//Adds a tag with the specified key and value to the list.
-func (l *guardedTagList[T]) Add(key string, value *T) {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+//Adds a tag with the specified key and value to the list. A tag at its limit returns a KeyLimit error
+//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+//that rejected the element, nothing is removed then.
+func (l *guardedTagList[T]) Add(key string, value *T) (*T, error) {
+	l.lock()
+	defer l.unlock()
 
 	if l.mapList[key] == nil {
-		l.mapList[key] = NewGuardedPointerList[T]()
+		l.mapList[key] = NewGuardedPointerList(l.options...)
 	}
 
-	l.mapList[key].Add(value)
+	//The tags hold lists of this package only.
+	return l.mapList[key].(listInternals[T]).insertLimited(key, value, l.limitOf(key), -1)
 }
 
 //Removes all elements from the GuardedTagList.
 func (l *guardedTagList[T]) Clear() {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
+	l.retireAll()
 	l.mapList = make(map[string]GuardedPointerList[T])
 }
 
 //Removes target list from the GuardedTagList.
 func (l *guardedTagList[T]) ClearList(key string) {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
+	l.retire(key)
 	l.mapList[key] = nil
 }
 
 //Determines whether a tag is in the GuardedTagList.
 func (l *guardedTagList[T]) Contains(key string, value *T) bool {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	if l.mapList[key] == nil {
 		return false
@@ -189,22 +350,28 @@ func (l *guardedTagList[T]) Contains(key string, value *T) bool {
 	return l.mapList[key].Contains(value)
 }
 
-//Inserts an element into the GuardedTagList at the specified index.
-func (l *guardedTagList[T]) Insert(index int, key string, value *T) {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+//Inserts an element into the GuardedTagList at the specified index. A tag at its limit returns a KeyLimit error
+//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+//that rejected the element, nothing is removed then.
+func (l *guardedTagList[T]) Insert(index int, key string, value *T) (*T, error) {
+	l.lock()
+	defer l.unlock()
 
 	if l.mapList[key] == nil {
-		l.mapList[key] = NewGuardedPointerList[T]()
+		l.mapList[key] = NewGuardedPointerList(l.options...)
 	}
 
-	l.mapList[key].Insert(value, index)
+	if index < 0 {
+		return nil, GetErrorf(IndexOutOfRange, index, l.mapList[key].Count())
+	}
+
+	return l.mapList[key].(listInternals[T]).insertLimited(key, value, l.limitOf(key), index)
 }
 
 //Removes the first occurrence of a specific object from the GuardedTagList.
 func (l *guardedTagList[T]) Remove(key string, value *T) bool {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	if l.mapList[key] == nil {
 		return false
@@ -215,8 +382,8 @@ func (l *guardedTagList[T]) Remove(key string, value *T) bool {
 
 //Removes the element at the specified index of the GuardedTagList.
 func (l *guardedTagList[T]) RemoveAt(key string, index int) bool {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	if l.mapList[key] == nil {
 		return false
@@ -227,8 +394,8 @@ func (l *guardedTagList[T]) RemoveAt(key string, index int) bool {
 
 //Searches for an element that matches the conditions defined by the specified predicate, and returns the first occurrence within the entire PointerList[T].
 func (l *guardedTagList[T]) Find(f FindPointerFunc[T]) *T {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	for _, list := range l.mapList {
 		for index, item := range list.ToArray() {
@@ -242,8 +409,8 @@ func (l *guardedTagList[T]) Find(f FindPointerFunc[T]) *T {
 }
 
 func (l *guardedTagList[T]) GetNextBefore(key string, f BeforeListFunc[T]) *T {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	if l.mapList[key] == nil {
 		return nil
@@ -266,8 +433,8 @@ func (l *guardedTagList[T]) GetNextBefore(key string, f BeforeListFunc[T]) *T {
 
 //Loop
 func (l *guardedTagList[T]) Foreach(f ForeachTagListFunc[T]) {
-	l.locker.Lock()
-	defer l.locker.Unlock()
+	l.lock()
+	defer l.unlock()
 
 	for key, list := range l.mapList {
 		for i := 0; i < list.Count(); i++ {
@@ -283,3 +450,188 @@ func (l *guardedTagList[T]) Foreach(f ForeachTagListFunc[T]) {
 		}
 	}
 }
+
+//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+func (l *guardedTagList[T]) MapCountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (map[string]int, error) {
+	l.lock()
+	defer l.unlock()
+
+	count := make(map[string]int)
+	visited := 0
+
+	for key, list := range l.mapList {
+		curCount := 0
+
+		if list != nil {
+			for index, current := range list.ToArray() {
+				if err := checkCtx(ctx, visited); err != nil {
+					return nil, err
+				}
+				visited++
+
+				if f(key, index, current) {
+					curCount++
+				}
+			}
+		}
+
+		count[key] = curCount
+	}
+
+	return count, nil
+}
+
+//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+func (l *guardedTagList[T]) CountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (int, error) {
+	l.lock()
+	defer l.unlock()
+
+	count := 0
+	visited := 0
+
+	for key, list := range l.mapList {
+		if list == nil {
+			continue
+		}
+
+		for index, current := range list.ToArray() {
+			if err := checkCtx(ctx, visited); err != nil {
+				return 0, err
+			}
+			visited++
+
+			if f(key, index, current) {
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+func (l *guardedTagList[T]) FindCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error) {
+	l.lock()
+	defer l.unlock()
+
+	visited := 0
+
+	for _, list := range l.mapList {
+		if list == nil {
+			continue
+		}
+
+		for index, item := range list.ToArray() {
+			if err := checkCtx(ctx, visited); err != nil {
+				return nil, err
+			}
+			visited++
+
+			if f(index, item) {
+				return item, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+//Loop. Returns ctx.Err() if the context is done. removeCurItem is applied once the walk completed, so a cancelled
+//walk removes nothing and the index passed to f is the index at the start of the walk.
+func (l *guardedTagList[T]) ForeachCtx(ctx context.Context, f ForeachTagListFunc[T]) error {
+	l.lock()
+	defer l.unlock()
+
+	removals, err := foreachTagsCtx[T](ctx, l.mapList, f)
+	if err != nil {
+		return err
+	}
+
+	applyTagRemovals(removals)
+
+	return nil
+}
+
+//Adds a tag with the specified key and value to the list. Returns false if the lock could not be taken within timeout,
+//or false and a KeyLimit error if the tag is at its limit with LimitReject, or the error of a Unique index.
+//Nothing is removed to make room unless the element is added.
+func (l *guardedTagList[T]) TryAdd(key string, value *T, timeout time.Duration) (bool, error) {
+	_, ok, err := l.tryAdd(key, value, timeout)
+	return ok, err
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns false if the lock could not be taken within timeout.
+func (l *guardedTagList[T]) TryFind(f FindPointerFunc[T], timeout time.Duration) (*T, bool) {
+	deadline := time.Now().Add(timeout)
+
+	if !l.lockWithin(timeout) {
+		return nil, false
+	}
+	defer l.unlock()
+
+	for _, list := range l.mapList {
+		if list == nil {
+			continue
+		}
+
+		item, ok := list.TryFind(f, time.Until(deadline))
+		if !ok {
+			return nil, false
+		}
+
+		if item != nil {
+			return item, true
+		}
+	}
+
+	return nil, true
+}
+
+//Same as TryAdd, also returning the element removed to make room.
+func (l *guardedTagList[T]) tryAdd(key string, value *T, timeout time.Duration) (*T, bool, error) {
+	deadline := time.Now().Add(timeout)
+
+	if !l.lockWithin(timeout) {
+		return nil, false, nil
+	}
+	defer l.unlock()
+
+	//A new list is kept only once the element was added.
+	list := l.mapList[key]
+	if list == nil {
+		list = NewGuardedPointerList(l.options...)
+	}
+
+	//The tags hold lists of this package only.
+	evicted, ok, err := list.(listInternals[T]).tryAddLimited(key, value, l.limitOf(key), time.Until(deadline))
+	if ok {
+		l.mapList[key] = list
+	}
+
+	return evicted, ok, err
+}
+
+//Locks the GuardedTagList. The OnExpire funcs of the elements that expire meanwhile are called by unlock.
+func (l *guardedTagList[T]) lock() {
+	l.locker.Lock()
+	l.expiring.hold()
+}
+
+//Same as lock within timeout.
+func (l *guardedTagList[T]) lockWithin(timeout time.Duration) bool {
+	if !tryLock(&l.locker, timeout) {
+		return false
+	}
+
+	l.expiring.hold()
+
+	return true
+}
+
+//Releases the GuardedTagList, then calls the OnExpire funcs held back by lock.
+func (l *guardedTagList[T]) unlock() {
+	expired := l.expiring.take()
+	l.locker.Unlock()
+
+	callAll(expired)
+}
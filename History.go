@@ -0,0 +1,476 @@
+package PointerList
+
+/////////////////////////////////
+//           History           //
+/////////////////////////////////
+
+//Records every change of the list for Undo and Redo, keeping the last maxSteps steps. maxSteps < 1 keeps every step.
+//The changes made while the list is locked once, or inside Batch, are one step. Sort, Reverse, Clear and the
+//replacing methods store a copy of the array. Elements expired by AddWithTTL are not part of the history, an Undo of
+//those replacing methods brings them back without a time-to-live. A change of a tag of a cache or a durable list made
+//by Undo is not seen by its eviction or its log.
+func WithHistory[T any](maxSteps int) ListOption[T] {
+	return func(l *pointerList[T]) {
+		l.history = &listHistory[T]{
+			maxSteps:    maxSteps,
+			checkpoints: make(map[string]int),
+		}
+	}
+}
+
+//Reverts the last step. Returns false if there is none or the list has no history.
+func (l *pointerList[T]) Undo() bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.history == nil {
+		return false
+	}
+
+	return l.history.undoStep()
+}
+
+//Applies the last reverted step again. Returns false if there is none or the list has no history.
+func (l *pointerList[T]) Redo() bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.history == nil {
+		return false
+	}
+
+	return l.history.redoStep()
+}
+
+//Determines whether there is a step to Undo.
+func (l *pointerList[T]) CanUndo() bool {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
+	}
+
+	return l.history != nil && (len(l.history.undo) > 0 || len(l.history.pending) > 0)
+}
+
+//Determines whether there is a step to Redo.
+func (l *pointerList[T]) CanRedo() bool {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
+	}
+
+	return l.history != nil && len(l.history.redo) > 0
+}
+
+//Names the current state of the list for RevertTo, replacing a checkpoint with the same name.
+func (l *pointerList[T]) Checkpoint(name string) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.history == nil {
+		return
+	}
+
+	l.history.commit()
+	l.history.checkpoints[name] = l.history.position()
+}
+
+//Undoes or redoes steps until the list is in the state named by Checkpoint. Returns an UnknownCheckpoint error if
+//the list has no history, or the steps of the checkpoint were dropped by maxSteps or by a change after an Undo.
+func (l *pointerList[T]) RevertTo(name string) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.history == nil {
+		return GetErrorf(UnknownCheckpoint, name)
+	}
+
+	return l.history.revertTo(name)
+}
+
+//Calls f and records the changes it makes as one step. f is called without the list lock, so it uses the methods of the list.
+func (l *pointerList[T]) Batch(f func()) {
+	if l.history == nil {
+		f()
+		return
+	}
+
+	l.start()
+	l.history.batch++
+	l.end()
+
+	defer func() {
+		l.start()
+		l.history.batch--
+		l.end()
+	}()
+
+	f()
+}
+
+//Reverts the last step of the tag. Returns false if there is none or the tag has no history.
+func (l *tagList[T]) Undo(key string) bool {
+	if l.mapList[key] == nil {
+		return false
+	}
+
+	return l.mapList[key].Undo()
+}
+
+//Applies the last reverted step of the tag again. Returns false if there is none or the tag has no history.
+func (l *tagList[T]) Redo(key string) bool {
+	if l.mapList[key] == nil {
+		return false
+	}
+
+	return l.mapList[key].Redo()
+}
+
+//Determines whether there is a step of the tag to Undo.
+func (l *tagList[T]) CanUndo(key string) bool {
+	return l.mapList[key] != nil && l.mapList[key].CanUndo()
+}
+
+//Determines whether there is a step of the tag to Redo.
+func (l *tagList[T]) CanRedo(key string) bool {
+	return l.mapList[key] != nil && l.mapList[key].CanRedo()
+}
+
+//Names the current state of the tag for RevertTo.
+func (l *tagList[T]) Checkpoint(key string, name string) {
+	if l.mapList[key] == nil {
+		l.mapList[key] = NewPointerList(l.options...)
+	}
+
+	l.mapList[key].Checkpoint(name)
+}
+
+//Undoes or redoes steps of the tag until it is in the state named by Checkpoint. ClearList drops the history of the tag.
+func (l *tagList[T]) RevertTo(key string, name string) error {
+	if l.mapList[key] == nil {
+		return GetErrorf(UnknownCheckpoint, name)
+	}
+
+	return l.mapList[key].RevertTo(name)
+}
+
+//Calls f and records the changes it makes to the tag as one step.
+func (l *tagList[T]) Batch(key string, f func()) {
+	if l.mapList[key] == nil {
+		l.mapList[key] = NewPointerList(l.options...)
+	}
+
+	l.mapList[key].Batch(f)
+}
+
+//Reverts the last step of the tag. Returns false if there is none or the tag has no history.
+func (l *guardedTagList[T]) Undo(key string) bool {
+	if list := l.listOf(key, false); list != nil {
+		return list.Undo()
+	}
+
+	return false
+}
+
+//Applies the last reverted step of the tag again. Returns false if there is none or the tag has no history.
+func (l *guardedTagList[T]) Redo(key string) bool {
+	if list := l.listOf(key, false); list != nil {
+		return list.Redo()
+	}
+
+	return false
+}
+
+//Determines whether there is a step of the tag to Undo.
+func (l *guardedTagList[T]) CanUndo(key string) bool {
+	list := l.listOf(key, false)
+	return list != nil && list.CanUndo()
+}
+
+//Determines whether there is a step of the tag to Redo.
+func (l *guardedTagList[T]) CanRedo(key string) bool {
+	list := l.listOf(key, false)
+	return list != nil && list.CanRedo()
+}
+
+//Names the current state of the tag for RevertTo.
+func (l *guardedTagList[T]) Checkpoint(key string, name string) {
+	l.listOf(key, true).Checkpoint(name)
+}
+
+//Undoes or redoes steps of the tag until it is in the state named by Checkpoint. ClearList and Clear drop the history of the tag.
+func (l *guardedTagList[T]) RevertTo(key string, name string) error {
+	if list := l.listOf(key, false); list != nil {
+		return list.RevertTo(name)
+	}
+
+	return GetErrorf(UnknownCheckpoint, name)
+}
+
+//Calls f and records the changes it makes to the tag as one step. f is called without the GuardedTagList lock.
+func (l *guardedTagList[T]) Batch(key string, f func()) {
+	l.listOf(key, true).Batch(f)
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//Returns the list of the tag, created if create is set. The list is used after the GuardedTagList was released.
+func (l *guardedTagList[T]) listOf(key string, create bool) GuardedPointerList[T] {
+	l.lock()
+	defer l.unlock()
+
+	if l.mapList[key] == nil && create {
+		l.mapList[key] = NewGuardedPointerList(l.options...)
+	}
+
+	return l.mapList[key]
+}
+
+type historyKind int
+
+const (
+	historyInsert historyKind = iota
+	historyRemove
+	historyReplace
+)
+
+//One invertible change of the list.
+type historyOp[T any] struct {
+	kind historyKind
+	at   int
+	item *T
+	//The arrays before and after a historyReplace.
+	before []*T
+	after  []*T
+}
+
+type historyStep[T any] struct {
+	id  int
+	ops []historyOp[T]
+}
+
+//Undo and redo steps of a list, called by the BASE of the owner.
+type listHistory[T any] struct {
+	owner       *pointerList[T]
+	maxSteps    int
+	undo        []historyStep[T]
+	redo        []historyStep[T]
+	pending     []historyOp[T]
+	lastID      int
+	dropped     int
+	checkpoints map[string]int
+	//Open Batch calls.
+	batch int
+	//Changes are not recorded while above 0.
+	suspended int
+}
+
+//Changes made without the BASE, by the NoSafe methods, are a step of their own.
+func (h *listHistory[T]) started() {
+	if h.batch == 0 {
+		h.commit()
+	}
+}
+
+func (h *listHistory[T]) released() func() {
+	if h.batch == 0 {
+		h.commit()
+	}
+
+	return nil
+}
+
+func (h *listHistory[T]) record(op historyOp[T]) {
+	if h.suspended == 0 {
+		h.pending = append(h.pending, op)
+	}
+}
+
+//The array was replaced or reordered. after is copied, the list may change it in place.
+func (h *listHistory[T]) replaced(before []*T, after []*T) {
+	if h.suspended == 0 {
+		h.record(historyOp[T]{kind: historyReplace, before: before, after: cloneArray(after)})
+	}
+}
+
+//Closes the pending changes as a step, which drops the steps that could be redone.
+func (h *listHistory[T]) commit() {
+	if len(h.pending) == 0 {
+		return
+	}
+
+	h.lastID++
+	h.undo = append(h.undo, historyStep[T]{id: h.lastID, ops: h.pending})
+	h.pending = nil
+	h.redo = nil
+
+	if h.maxSteps > 0 && len(h.undo) > h.maxSteps {
+		h.dropped = h.undo[0].id
+		h.undo[0] = historyStep[T]{}
+		h.undo = h.undo[1:]
+	}
+}
+
+//Id of the last step applied, or of the last dropped step if every step was undone.
+func (h *listHistory[T]) position() int {
+	if len(h.undo) == 0 {
+		return h.dropped
+	}
+
+	return h.undo[len(h.undo)-1].id
+}
+
+func (h *listHistory[T]) undoStep() bool {
+	//An Undo inside Batch ends the step of the batch.
+	h.commit()
+
+	if len(h.undo) == 0 {
+		return false
+	}
+
+	step := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+
+	h.suspended++
+	for i := len(step.ops) - 1; i >= 0; i-- {
+		step.ops[i].revert(h.owner)
+	}
+	h.suspended--
+
+	h.redo = append(h.redo, step)
+	return true
+}
+
+func (h *listHistory[T]) redoStep() bool {
+	h.commit()
+
+	if len(h.redo) == 0 {
+		return false
+	}
+
+	step := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+
+	h.suspended++
+	for i := 0; i < len(step.ops); i++ {
+		step.ops[i].apply(h.owner)
+	}
+	h.suspended--
+
+	h.undo = append(h.undo, step)
+	return true
+}
+
+func (h *listHistory[T]) revertTo(name string) error {
+	h.commit()
+
+	id, ok := h.checkpoints[name]
+	if !ok || !h.reachable(id) {
+		return GetErrorf(UnknownCheckpoint, name)
+	}
+
+	for h.position() > id && h.undoStep() {
+	}
+
+	for h.position() != id && h.redoStep() {
+	}
+
+	return nil
+}
+
+//Determines whether the step id can be reached by Undo or Redo.
+func (h *listHistory[T]) reachable(id int) bool {
+	if id == h.dropped {
+		return true
+	}
+
+	for _, step := range h.undo {
+		if step.id == id {
+			return true
+		}
+	}
+
+	for _, step := range h.redo {
+		if step.id == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (op historyOp[T]) revert(l *pointerList[T]) {
+	switch op.kind {
+	case historyInsert:
+		l.removeFrom(op.item, op.at)
+	case historyRemove:
+		l.insertAt(op.item, op.at)
+	case historyReplace:
+		l.restore(op.before)
+	}
+}
+
+func (op historyOp[T]) apply(l *pointerList[T]) {
+	switch op.kind {
+	case historyInsert:
+		l.insertAt(op.item, op.at)
+	case historyRemove:
+		l.removeFrom(op.item, op.at)
+	case historyReplace:
+		l.restore(op.after)
+	}
+}
+
+//Inserts the element at the index, or at the end if an expired element made the list shorter.
+func (l *pointerList[T]) insertAt(item *T, at int) {
+	at = minInt(at, len(l.list))
+
+	l.list, _ = sliceInsert(l.list, item, at)
+	l.indexAdd(item, at)
+}
+
+//Removes the element at the index, or where an expired element moved it.
+func (l *pointerList[T]) removeFrom(item *T, at int) {
+	if at >= len(l.list) || l.list[at] != item {
+		at = sliceFindIndex(l.list, func(index int, current *T) bool {
+			return current == item
+		})
+	}
+
+	if at >= 0 {
+		l.removeAt(at)
+	}
+}
+
+//Replaces the elements with a copy of items, the history keeps items.
+func (l *pointerList[T]) restore(items []*T) {
+	l.list = cloneArray(items)
+	l.lastIndex = 0
+	l.reindexAll(nil)
+}
+
+func cloneArray[T any](items []*T) []*T {
+	clone := make([]*T, len(items))
+	copy(clone, items)
+
+	return clone
+}
+
+//Copy of the array for the history of a change in place, nil without a history.
+func (l *pointerList[T]) historyBefore() []*T {
+	if l.history == nil || l.history.suspended > 0 {
+		return nil
+	}
+
+	return cloneArray(l.list)
+}
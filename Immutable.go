@@ -0,0 +1,513 @@
+package PointerList
+
+/////////////////////////////////
+//       Immutable List        //
+/////////////////////////////////
+
+//Persistent list, every change returns a new ImmutableList[T] and leaves the old one as it was. The versions share
+//their structure: a 32-way trie of full leaves plus a tail holding the last elements. Get, Add and Set are O(log32 n),
+//Insert and Remove share the leaves before the changed index and rebuild the rest, O(n).
+type ImmutableList[T any] interface {
+	//Returns the number of elements in a sequence.
+	Count() int
+	//Gets the element at the specified index.
+	Get(index int) *T
+	//Returns a list with the object added to the end.
+	Add(item *T) ImmutableList[T]
+	//Returns a list with the element at the specified index replaced.
+	Set(index int, item *T) (ImmutableList[T], error)
+	//Returns a list with the element inserted at the specified index.
+	Insert(item *T, index int) (ImmutableList[T], error)
+	//Returns a list without the first occurrence of a specific object. Returns false if it is not in the list.
+	Remove(targetItem *T) (ImmutableList[T], bool)
+	//Returns a list without the element at the specified index. Returns false if the index is out of range.
+	RemoveAt(index int) (ImmutableList[T], bool)
+	//Copies the elements.
+	ToArray() []*T
+	//Copies the elements into a new PointerList[T].
+	ToPointerList(options ...ListOption[T]) PointerList[T]
+	//Searches for an element that matches the conditions defined by the specified predicate, and returns the first occurrence.
+	Find(f FindPointerFunc[T]) *T
+	//Loop
+	Foreach(f ForeachListFunc[T])
+	//Returns a builder starting with the elements of the list, for bulk edits without a new version per change.
+	Transient() TransientList[T]
+}
+
+//Builder of an ImmutableList[T]. It changes its own nodes in place and copies the nodes it shares with other lists.
+type TransientList[T any] interface {
+	//Returns the number of elements in a sequence.
+	Count() int
+	//Gets the element at the specified index.
+	Get(index int) *T
+	//Adds an object to the end.
+	Add(item *T)
+	//Adds the elements of the specified collection to the end.
+	AddRange(items []*T)
+	//Replaces the element at the specified index.
+	Set(index int, item *T) error
+	//Returns the built list. The builder can be used further, the returned list does not change.
+	Persistent() ImmutableList[T]
+}
+
+const (
+	immutableBits  = 5
+	immutableWidth = 1 << immutableBits
+	immutableMask  = immutableWidth - 1
+)
+
+type immutableList[T any] struct {
+	immutableTrie[T]
+}
+
+type transientList[T any] struct {
+	immutableTrie[T]
+	//Owner of the nodes that are changed in place.
+	edit *int
+	//The tail is a leaf of another list and is copied before it is changed.
+	sharedTail bool
+}
+
+func NewImmutableList[T any]() ImmutableList[T] {
+	return &immutableList[T]{
+		immutableTrie: newImmutableTrie[T](),
+	}
+}
+
+//Copies the elements of the PointerList[T] into a new ImmutableList[T].
+func NewImmutableListFrom[T any](list PointerList[T]) ImmutableList[T] {
+	t := newTransientList[T]()
+	t.AddRange(snapshotOf[T](list))
+
+	return t.Persistent()
+}
+
+//Returns a list with the object added to the end.
+func (l *immutableList[T]) Add(item *T) ImmutableList[T] {
+	if l.count-l.tailOffset() < immutableWidth {
+		tail := make([]*T, len(l.tail)+1)
+		copy(tail, l.tail)
+		tail[len(l.tail)] = item
+
+		return l.with(l.count+1, l.shift, l.root, tail)
+	}
+
+	root, shift := l.pushTail(nil, &immutableNode[T]{items: l.tail})
+
+	return l.with(l.count+1, shift, root, []*T{item})
+}
+
+//Returns a list with the element at the specified index replaced.
+func (l *immutableList[T]) Set(index int, item *T) (ImmutableList[T], error) {
+	if index < 0 || index >= l.count {
+		return l, GetErrorf(IndexOutOfRange, index, l.count)
+	}
+
+	if index >= l.tailOffset() {
+		tail := make([]*T, len(l.tail))
+		copy(tail, l.tail)
+		tail[index&immutableMask] = item
+
+		return l.with(l.count, l.shift, l.root, tail), nil
+	}
+
+	return l.with(l.count, l.shift, l.assoc(nil, l.shift, l.root, index, item), l.tail), nil
+}
+
+//Returns a list with the element inserted at the specified index.
+func (l *immutableList[T]) Insert(item *T, index int) (ImmutableList[T], error) {
+	if index < 0 || index > l.count {
+		return l, GetErrorf(IndexOutOfRange, index, l.count)
+	}
+
+	return l.splice(index, 0, item), nil
+}
+
+//Returns a list without the first occurrence of a specific object. Returns false if it is not in the list.
+func (l *immutableList[T]) Remove(targetItem *T) (ImmutableList[T], bool) {
+	index := -1
+
+	l.each(0, func(i int, current *T) bool {
+		if current == targetItem {
+			index = i
+			return false
+		}
+
+		return true
+	})
+
+	return l.RemoveAt(index)
+}
+
+//Returns a list without the element at the specified index. Returns false if the index is out of range.
+func (l *immutableList[T]) RemoveAt(index int) (ImmutableList[T], bool) {
+	if index < 0 || index >= l.count {
+		return l, false
+	}
+
+	return l.splice(index, 1), true
+}
+
+//Copies the elements.
+func (l *immutableList[T]) ToArray() []*T {
+	items := make([]*T, 0, l.count)
+
+	l.each(0, func(index int, current *T) bool {
+		items = append(items, current)
+		return true
+	})
+
+	return items
+}
+
+//Copies the elements into a new PointerList[T].
+func (l *immutableList[T]) ToPointerList(options ...ListOption[T]) PointerList[T] {
+	return newPointerList(l.ToArray(), nil, options)
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate, and returns the first occurrence.
+func (l *immutableList[T]) Find(f FindPointerFunc[T]) *T {
+	var item *T
+
+	l.each(0, func(index int, current *T) bool {
+		if f(index, current) {
+			item = current
+			return false
+		}
+
+		return true
+	})
+
+	return item
+}
+
+//Loop
+func (l *immutableList[T]) Foreach(f ForeachListFunc[T]) {
+	l.each(0, f)
+}
+
+//Returns a builder starting with the elements of the list, for bulk edits without a new version per change.
+func (l *immutableList[T]) Transient() TransientList[T] {
+	t := &transientList[T]{
+		immutableTrie: l.immutableTrie,
+		edit:          new(int),
+	}
+
+	t.tail = make([]*T, len(l.tail), immutableWidth)
+	copy(t.tail, l.tail)
+
+	return t
+}
+
+//Adds an object to the end.
+func (t *transientList[T]) Add(item *T) {
+	if t.count-t.tailOffset() < immutableWidth {
+		t.ownTail()
+		t.tail = append(t.tail, item)
+		t.count++
+
+		return
+	}
+
+	t.pushTailNode()
+
+	t.tail = make([]*T, 1, immutableWidth)
+	t.tail[0] = item
+	t.count++
+}
+
+//Adds the elements of the specified collection to the end.
+func (t *transientList[T]) AddRange(items []*T) {
+	for _, item := range items {
+		t.Add(item)
+	}
+}
+
+//Replaces the element at the specified index.
+func (t *transientList[T]) Set(index int, item *T) error {
+	if index < 0 || index >= t.count {
+		return GetErrorf(IndexOutOfRange, index, t.count)
+	}
+
+	if index >= t.tailOffset() {
+		t.ownTail()
+		t.tail[index&immutableMask] = item
+
+		return nil
+	}
+
+	t.root = t.assoc(t.edit, t.shift, t.root, index, item)
+
+	return nil
+}
+
+//Returns the built list. The builder can be used further, the returned list does not change.
+func (t *transientList[T]) Persistent() ImmutableList[T] {
+	l := &immutableList[T]{
+		immutableTrie: t.immutableTrie,
+	}
+
+	l.tail = make([]*T, len(t.tail))
+	copy(l.tail, t.tail)
+
+	//The nodes are shared with l from now on.
+	t.edit = new(int)
+
+	return l
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//Node of the trie. A leaf holds 32 elements, an inner node up to 32 children.
+type immutableNode[T any] struct {
+	//Transient owner, nil for a node of an ImmutableList[T].
+	edit     *int
+	children []*immutableNode[T]
+	items    []*T
+}
+
+//Returns the node if it is owned by edit, otherwise a copy owned by edit.
+func (n *immutableNode[T]) editable(edit *int) *immutableNode[T] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+
+	node := &immutableNode[T]{
+		edit: edit,
+	}
+
+	if n.children != nil {
+		node.children = make([]*immutableNode[T], len(n.children), immutableWidth)
+		copy(node.children, n.children)
+	}
+
+	if n.items != nil {
+		node.items = make([]*T, len(n.items))
+		copy(node.items, n.items)
+	}
+
+	return node
+}
+
+//Chain of inner nodes from level down to the leaf.
+func newImmutablePath[T any](edit *int, level uint, leaf *immutableNode[T]) *immutableNode[T] {
+	if level == 0 {
+		return leaf
+	}
+
+	return &immutableNode[T]{
+		edit:     edit,
+		children: []*immutableNode[T]{newImmutablePath(edit, level-immutableBits, leaf)},
+	}
+}
+
+//State shared by immutableList and transientList.
+type immutableTrie[T any] struct {
+	count int
+	shift uint
+	root  *immutableNode[T]
+	tail  []*T
+}
+
+func newImmutableTrie[T any]() immutableTrie[T] {
+	return immutableTrie[T]{
+		shift: immutableBits,
+		root:  &immutableNode[T]{},
+		tail:  make([]*T, 0),
+	}
+}
+
+func newTransientList[T any]() *transientList[T] {
+	t := &transientList[T]{
+		immutableTrie: newImmutableTrie[T](),
+		edit:          new(int),
+	}
+
+	t.tail = make([]*T, 0, immutableWidth)
+
+	return t
+}
+
+//Returns the number of elements in a sequence.
+func (t *immutableTrie[T]) Count() int {
+	return t.count
+}
+
+//Gets the element at the specified index.
+func (t *immutableTrie[T]) Get(index int) *T {
+	if index < 0 || index >= t.count {
+		return nil
+	}
+
+	return t.leafFor(index)[index&immutableMask]
+}
+
+//Index of the first element of the tail.
+func (t *immutableTrie[T]) tailOffset() int {
+	if t.count < immutableWidth {
+		return 0
+	}
+
+	return ((t.count - 1) >> immutableBits) << immutableBits
+}
+
+//Returns the leaf or the tail holding the element at the specified index.
+func (t *immutableTrie[T]) leafFor(index int) []*T {
+	if index >= t.tailOffset() {
+		return t.tail
+	}
+
+	node := t.root
+
+	for level := t.shift; level > 0; level -= immutableBits {
+		node = node.children[(index>>level)&immutableMask]
+	}
+
+	return node.items
+}
+
+//Calls f for every element from the specified index on, until f returns false.
+func (t *immutableTrie[T]) each(from int, f func(index int, current *T) bool) {
+	for index := from; index < t.count; {
+		leaf := t.leafFor(index)
+
+		for i := index & immutableMask; i < len(leaf) && index < t.count; i++ {
+			if !f(index, leaf[i]) {
+				return
+			}
+
+			index++
+		}
+	}
+}
+
+//Adds the full tail node to the trie. Returns the new root and shift, nodes owned by edit are changed in place.
+func (t *immutableTrie[T]) pushTail(edit *int, tailNode *immutableNode[T]) (*immutableNode[T], uint) {
+	//The root is full.
+	if (t.count >> immutableBits) > (1 << t.shift) {
+		root := &immutableNode[T]{
+			edit:     edit,
+			children: []*immutableNode[T]{t.root, newImmutablePath(edit, t.shift, tailNode)},
+		}
+
+		return root, t.shift + immutableBits
+	}
+
+	return t.pushTailAt(edit, t.shift, t.root, tailNode), t.shift
+}
+
+func (t *immutableTrie[T]) pushTailAt(edit *int, level uint, parent *immutableNode[T], tailNode *immutableNode[T]) *immutableNode[T] {
+	node := parent.editable(edit)
+	index := ((t.count - 1) >> level) & immutableMask
+
+	var child *immutableNode[T]
+
+	switch {
+	case level == immutableBits:
+		child = tailNode
+	case index < len(parent.children):
+		child = t.pushTailAt(edit, level-immutableBits, parent.children[index], tailNode)
+	default:
+		child = newImmutablePath(edit, level-immutableBits, tailNode)
+	}
+
+	if index < len(node.children) {
+		node.children[index] = child
+	} else {
+		node.children = append(node.children, child)
+	}
+
+	return node
+}
+
+//Replaces the element below node, nodes owned by edit are changed in place.
+func (t *immutableTrie[T]) assoc(edit *int, level uint, node *immutableNode[T], index int, item *T) *immutableNode[T] {
+	result := node.editable(edit)
+
+	if level == 0 {
+		result.items[index&immutableMask] = item
+		return result
+	}
+
+	child := (index >> level) & immutableMask
+	result.children[child] = t.assoc(edit, level-immutableBits, node.children[child], index, item)
+
+	return result
+}
+
+func (l *immutableList[T]) with(count int, shift uint, root *immutableNode[T], tail []*T) *immutableList[T] {
+	return &immutableList[T]{
+		immutableTrie: immutableTrie[T]{
+			count: count,
+			shift: shift,
+			root:  root,
+			tail:  tail,
+		},
+	}
+}
+
+//Returns a list with skip elements at index replaced by items. The leaves before index are shared.
+func (l *immutableList[T]) splice(index int, skip int, items ...*T) ImmutableList[T] {
+	t := newTransientList[T]()
+	full := minInt(index, l.tailOffset()) &^ immutableMask
+
+	for start := 0; start < full; start += immutableWidth {
+		t.addLeaf(l.leafFor(start))
+	}
+
+	l.each(full, func(i int, current *T) bool {
+		if i == index {
+			return false
+		}
+
+		t.Add(current)
+		return true
+	})
+
+	t.AddRange(items)
+
+	l.each(index+skip, func(i int, current *T) bool {
+		t.Add(current)
+		return true
+	})
+
+	return t.Persistent()
+}
+
+//Adds a full leaf of another list without copying it. The count is a multiple of 32.
+func (t *transientList[T]) addLeaf(items []*T) {
+	if t.count > 0 {
+		t.pushTailNode()
+	}
+
+	t.tail = items
+	t.sharedTail = true
+	t.count += immutableWidth
+}
+
+//Moves the full tail into the trie.
+func (t *transientList[T]) pushTailNode() {
+	//A shared leaf must not be changed in place.
+	owner := t.edit
+	if t.sharedTail {
+		owner = nil
+	}
+
+	t.root, t.shift = t.pushTail(t.edit, &immutableNode[T]{edit: owner, items: t.tail})
+	t.sharedTail = false
+}
+
+//Copies a shared tail before it is changed.
+func (t *transientList[T]) ownTail() {
+	if !t.sharedTail {
+		return
+	}
+
+	tail := make([]*T, len(t.tail), immutableWidth)
+	copy(tail, t.tail)
+
+	t.tail = tail
+	t.sharedTail = false
+}
@@ -0,0 +1,498 @@
+package PointerList
+
+import "reflect"
+
+/////////////////////////////////
+//            Index            //
+/////////////////////////////////
+
+type IndexOption func(o *indexOptions)
+
+type indexOptions struct {
+	unique bool
+}
+
+//Rejects elements whose key is already in the index.
+func Unique() IndexOption {
+	return func(o *indexOptions) {
+		o.unique = true
+	}
+}
+
+//Returns an IndexKeyFunc[T] reading the field of T named by its `list:"name"` struct tag or its field name.
+func FieldKey[T any](name string) (IndexKeyFunc[T], error) {
+	fields, err := csvFields[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		if field.name == name {
+			index := field.index
+
+			return func(current *T) any {
+				return reflect.ValueOf(current).Elem().Field(index).Interface()
+			}, nil
+		}
+	}
+
+	return nil, GetErrorf(UnknownField, name)
+}
+
+//Creates or replaces the named index of the list with keys of type K, which can always be hashed.
+//Same as the CreateIndex method otherwise.
+func CreateIndex[T any, K comparable](l PointerList[T], name string, keyFn KeyPointerFunc[T, K], options ...IndexOption) error {
+	indexKeyFn := func(current *T) any {
+		return keyFn(current)
+	}
+
+	if list, ok := l.(listInternals[T]); ok {
+		return list.createIndex(name, indexKeyFn, reflect.TypeOf((*K)(nil)).Elem(), options)
+	}
+
+	return l.CreateIndex(name, indexKeyFn, options...)
+}
+
+//Returns the elements with the specified key in the named index of the list, in no particular order.
+func Lookup[T any, K comparable](l PointerList[T], name string, key K) []*T {
+	return l.Lookup(name, key)
+}
+
+//Creates or replaces the named index over the current elements. Nil elements and elements whose key can not be
+//hashed, such as a slice, are not indexed. CreateIndex[T, K] takes a typed key func instead.
+//Returns DuplicateKey if the index is Unique and two elements share a key, the index is not created.
+func (l *pointerList[T]) CreateIndex(name string, keyFn IndexKeyFunc[T], options ...IndexOption) error {
+	return l.createIndex(name, keyFn, nil, options)
+}
+
+//Removes the named index.
+func (l *pointerList[T]) DropIndex(name string) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if _, ok := l.indexes[name]; !ok {
+		return false
+	}
+
+	delete(l.indexes, name)
+
+	return true
+}
+
+//Returns the elements with the specified key in the named index, in no particular order.
+//A number is converted to the type of the keys if it fits, so Lookup(name, 1) finds the int64 key 1.
+func (l *pointerList[T]) Lookup(name string, key any) []*T {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
+	}
+
+	index, ok := l.indexes[name]
+	if !ok {
+		return nil
+	}
+
+	key = index.convert(key)
+
+	items := make([]*T, len(index.entries[key]))
+	copy(items, index.entries[key])
+
+	return items
+}
+
+//Recomputes the keys of an element after it was changed. Returns DuplicateKey if a Unique index
+//already holds another element with the new key, no index is changed then.
+func (l *pointerList[T]) Reindex(item *T) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if item == nil {
+		return nil
+	}
+
+	for _, index := range l.indexes {
+		if _, ok := index.keys[item]; !ok || !index.unique {
+			continue
+		}
+
+		key, ok := index.keyOf(item)
+		if !ok {
+			continue
+		}
+
+		for _, current := range index.entries[key] {
+			if current != item {
+				return GetErrorf(DuplicateKey, key, index.name)
+			}
+		}
+	}
+
+	for _, index := range l.allIndexes() {
+		indexed, ok := index.keys[item]
+		if !ok {
+			continue
+		}
+
+		for i := 0; i < indexed.count; i++ {
+			index.remove(item)
+		}
+
+		for i := 0; i < indexed.count; i++ {
+			index.add(item)
+		}
+	}
+
+	return nil
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func (l *pointerList[T]) createIndex(name string, keyFn IndexKeyFunc[T], keyType reflect.Type, options []IndexOption) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	opts := indexOptions{}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	index := newListIndex(name, keyFn, opts.unique)
+	index.keyType = keyType
+	index.typed = keyType != nil
+
+	for i := 0; i < len(l.list); i++ {
+		if !index.accepts(l.list[i]) {
+			return GetErrorf(DuplicateKey, keyFn(l.list[i]), name)
+		}
+
+		index.add(l.list[i])
+	}
+
+	if l.indexes == nil {
+		l.indexes = make(map[string]*listIndex[T])
+	}
+
+	l.indexes[name] = index
+
+	return nil
+}
+
+type listIndex[T any] struct {
+	name   string
+	key    IndexKeyFunc[T]
+	unique bool
+	//Type of the keys, from CreateIndex[T, K] or the first indexed key.
+	keyType reflect.Type
+	//The keys are of a comparable type, they are hashed without a check.
+	typed   bool
+	entries map[any][]*T
+	//Key at the time the element was indexed, so it can be removed after it changed.
+	keys map[*T]indexedKey
+}
+
+type indexedKey struct {
+	key   any
+	count int
+}
+
+func newListIndex[T any](name string, keyFn IndexKeyFunc[T], unique bool) *listIndex[T] {
+	return &listIndex[T]{
+		name:    name,
+		key:     keyFn,
+		unique:  unique,
+		entries: make(map[any][]*T),
+		keys:    make(map[*T]indexedKey),
+	}
+}
+
+//Empty index with the same key func and options.
+func (index *listIndex[T]) reset() *listIndex[T] {
+	empty := newListIndex(index.name, index.key, index.unique)
+	empty.keyType = index.keyType
+	empty.typed = index.typed
+
+	return empty
+}
+
+//Returns the key of the element, false for a nil element or a key that can not be hashed. Neither is indexed.
+func (index *listIndex[T]) keyOf(item *T) (any, bool) {
+	if item == nil {
+		return nil, false
+	}
+
+	key := index.key(item)
+
+	if !index.typed && !hashable(key) {
+		return nil, false
+	}
+
+	return key, true
+}
+
+//Determines whether the key can be a map key. A slice, a map or a func, also inside a struct or an array, can not.
+func hashable(key any) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	_ = key == key
+
+	return true
+}
+
+//Converts a number to the type of the keys if it fits without loss.
+func (index *listIndex[T]) convert(key any) any {
+	if index.keyType == nil || key == nil {
+		return key
+	}
+
+	value := reflect.ValueOf(key)
+
+	if value.Type() == index.keyType || !isNumberKind(value.Kind()) || !isNumberKind(index.keyType.Kind()) {
+		return key
+	}
+
+	converted := value.Convert(index.keyType)
+
+	if converted.Convert(value.Type()).Interface() != key {
+		return key
+	}
+
+	return converted.Interface()
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+func (index *listIndex[T]) accepts(item *T) bool {
+	key, ok := index.keyOf(item)
+	if !ok || !index.unique {
+		return true
+	}
+
+	return len(index.entries[key]) == 0
+}
+
+func (index *listIndex[T]) add(item *T) {
+	indexed, ok := index.keys[item]
+	if !ok {
+		if indexed.key, ok = index.keyOf(item); !ok {
+			return
+		}
+
+		if index.keyType == nil && indexed.key != nil {
+			index.keyType = reflect.TypeOf(indexed.key)
+		}
+	}
+
+	indexed.count++
+	index.keys[item] = indexed
+	index.entries[indexed.key] = append(index.entries[indexed.key], item)
+}
+
+func (index *listIndex[T]) remove(item *T) {
+	indexed, ok := index.keys[item]
+	if !ok {
+		return
+	}
+
+	if indexed.count--; indexed.count == 0 {
+		delete(index.keys, item)
+	} else {
+		index.keys[item] = indexed
+	}
+
+	entries := index.entries[indexed.key]
+
+	for i := 0; i < len(entries); i++ {
+		if entries[i] == item {
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+
+	if len(entries) == 0 {
+		delete(index.entries, indexed.key)
+	} else {
+		index.entries[indexed.key] = entries
+	}
+}
+
+//Reports the first index that rejects the element, or nil.
+func (l *pointerList[T]) indexRejects(item *T) error {
+	if l.positions != nil && l.has(item) {
+		return GetError(DuplicateItem)
+	}
+
+	for _, index := range l.indexes {
+		if !index.accepts(item) {
+			return GetErrorf(DuplicateKey, index.key(item), index.name)
+		}
+	}
+
+	return nil
+}
+
+//Same as indexRejects for several elements, including duplicates among them.
+func (l *pointerList[T]) indexRejectsRange(items []*T) error {
+	if l.positions != nil {
+		if len(l.distinct(items)) != len(items) {
+			return GetError(DuplicateItem)
+		}
+
+		for _, item := range items {
+			if l.has(item) {
+				return GetError(DuplicateItem)
+			}
+		}
+	}
+
+	for _, index := range l.indexes {
+		if !index.unique {
+			continue
+		}
+
+		seen := make(map[any]bool, len(items))
+
+		for _, item := range items {
+			key, ok := index.keyOf(item)
+			if !ok {
+				continue
+			}
+
+			if seen[key] || !index.accepts(item) {
+				return GetErrorf(DuplicateKey, key, index.name)
+			}
+
+			seen[key] = true
+		}
+	}
+
+	return nil
+}
+
+//The named indexes and the key index of WithKey.
+func (l *pointerList[T]) allIndexes() []*listIndex[T] {
+	indexes := make([]*listIndex[T], 0, len(l.indexes)+1)
+
+	for _, index := range l.indexes {
+		indexes = append(indexes, index)
+	}
+
+	if l.keyIndex != nil {
+		indexes = append(indexes, l.keyIndex)
+	}
+
+	return indexes
+}
+
+//Reports whether Add has to check or update any index.
+func (l *pointerList[T]) indexed() bool {
+	return len(l.indexes) > 0 || l.positions != nil || l.keyIndex != nil || l.history != nil
+}
+
+//The element was placed at the specified index.
+func (l *pointerList[T]) indexAdd(item *T, at int) {
+	l.version++
+
+	if l.positions != nil {
+		l.positions.add(item, at, len(l.list))
+	}
+
+	if l.keyIndex != nil {
+		l.keyIndex.add(item)
+	}
+
+	for _, index := range l.indexes {
+		index.add(item)
+	}
+
+	if l.history != nil {
+		l.history.record(historyOp[T]{kind: historyInsert, at: at, item: item})
+	}
+}
+
+//The element was removed from the specified index.
+func (l *pointerList[T]) indexRemove(item *T, at int) {
+	l.version++
+
+	if l.positions != nil {
+		l.positions.remove(item, at)
+	}
+
+	if l.keyIndex != nil {
+		l.keyIndex.remove(item)
+	}
+
+	if l.ttl != nil {
+		l.ttl.forget(item)
+	}
+
+	for _, index := range l.indexes {
+		index.remove(item)
+	}
+
+	if l.history != nil {
+		l.history.record(historyOp[T]{kind: historyRemove, at: at, item: item})
+	}
+}
+
+//The elements were reordered, before is the array as it was or nil.
+func (l *pointerList[T]) indexMoved(before []*T) {
+	l.version++
+
+	if l.positions != nil {
+		l.positions.moved(0)
+	}
+
+	if l.history != nil && before != nil {
+		l.history.replaced(before, l.list)
+	}
+}
+
+//Rebuilds every index from the current elements, before is the array as it was or nil.
+func (l *pointerList[T]) reindexAll(before []*T) {
+	l.version++
+
+	if l.history != nil && before != nil {
+		l.history.replaced(before, l.list)
+	}
+
+	if l.positions != nil {
+		l.positions.rebuild(l.list)
+	}
+
+	if l.keyIndex != nil {
+		l.keyIndex = l.keyIndex.reset()
+
+		for i := 0; i < len(l.list); i++ {
+			l.keyIndex.add(l.list[i])
+		}
+	}
+
+	if l.ttl != nil {
+		l.ttl.retain(l.list)
+	}
+
+	for name, index := range l.indexes {
+		rebuilt := index.reset()
+
+		for i := 0; i < len(l.list); i++ {
+			rebuilt.add(l.list[i])
+		}
+
+		l.indexes[name] = rebuilt
+	}
+}
@@ -0,0 +1,262 @@
+package PointerList
+
+import (
+	"encoding/json"
+	"io"
+)
+
+/////////////////////////////////
+//             JSON            //
+/////////////////////////////////
+
+//Encodes the PointerList[T] as a JSON array, nil elements are encoded as null.
+func (l *pointerList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.snapshot())
+}
+
+//Replaces the elements of the PointerList[T] with the decoded JSON array.
+func (l *pointerList[T]) UnmarshalJSON(data []byte) error {
+	items := make([]*T, 0)
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	if items == nil {
+		items = make([]*T, 0)
+	}
+
+	l.replace(items)
+
+	return nil
+}
+
+//Encodes the TagList as a JSON object keyed by tag.
+func (l *tagList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.ToArrayMap())
+}
+
+//Replaces the tags of the TagList with the decoded JSON object.
+func (l *tagList[T]) UnmarshalJSON(data []byte) error {
+	items := make(map[string][]*T)
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	l.replaceMap(items)
+
+	return nil
+}
+
+//Encodes the GuardedTagList as a JSON object keyed by tag.
+func (l *guardedTagList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.ToArrayMap())
+}
+
+//Replaces the tags of the GuardedTagList with the decoded JSON object.
+func (l *guardedTagList[T]) UnmarshalJSON(data []byte) error {
+	items := make(map[string][]*T)
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	l.replaceMap(items)
+
+	return nil
+}
+
+/////////////////////////////////
+//           Streaming         //
+/////////////////////////////////
+
+//Writes lists as JSON element by element, without building the whole document in memory.
+type Encoder[T any] struct {
+	w io.Writer
+}
+
+func NewEncoder[T any](w io.Writer) *Encoder[T] {
+	return &Encoder[T]{
+		w: w,
+	}
+}
+
+//Writes the PointerList[T] as a JSON array.
+func (e *Encoder[T]) Encode(l PointerList[T]) error {
+	if _, err := io.WriteString(e.w, "["); err != nil {
+		return err
+	}
+
+	if err := e.encodeItems(snapshotOf[T](l)); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+//Writes the TagList or GuardedTagList as a JSON object keyed by tag.
+func (e *Encoder[T]) EncodeTags(l TagSource[T]) error {
+	if _, err := io.WriteString(e.w, "{"); err != nil {
+		return err
+	}
+
+	first := true
+
+	for key, items := range l.ToArrayMap() {
+		data, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			data = append([]byte(","), data...)
+		}
+		first = false
+
+		if _, err := e.w.Write(append(data, ':')); err != nil {
+			return err
+		}
+
+		if items == nil {
+			if _, err := io.WriteString(e.w, "null"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := io.WriteString(e.w, "["); err != nil {
+			return err
+		}
+
+		if err := e.encodeItems(items); err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(e.w, "]"); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(e.w, "}\n")
+	return err
+}
+
+func (e *Encoder[T]) encodeItems(items []*T) error {
+	for i := 0; i < len(items); i++ {
+		data, err := json.Marshal(items[i])
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			data = append([]byte(","), data...)
+		}
+
+		if _, err := e.w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//Reads lists from JSON element by element, without holding the whole document in memory.
+type Decoder[T any] struct {
+	dec *json.Decoder
+}
+
+func NewDecoder[T any](r io.Reader) *Decoder[T] {
+	return &Decoder[T]{
+		dec: json.NewDecoder(r),
+	}
+}
+
+//Reads the next JSON array and adds its elements to the end of the PointerList[T].
+func (d *Decoder[T]) Decode(l PointerList[T]) error {
+	isNull, err := d.expectDelim('[')
+	if err != nil || isNull {
+		return err
+	}
+
+	return d.decodeItems(l.Add)
+}
+
+//Reads the next JSON object and adds its elements to the TagList or GuardedTagList. Stops at the first error of Add,
+//the rest of the object is not read.
+func (d *Decoder[T]) DecodeTags(l interface {
+	Add(key string, value *T) (*T, error)
+}) error {
+	isNull, err := d.expectDelim('{')
+	if err != nil || isNull {
+		return err
+	}
+
+	for d.dec.More() {
+		token, err := d.dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key := token.(string)
+
+		isNull, err := d.expectDelim('[')
+		if err != nil {
+			return err
+		} else if isNull {
+			continue
+		}
+
+		var addErr error
+
+		err = d.decodeItems(func(item *T) {
+			if addErr == nil {
+				_, addErr = l.Add(key, item)
+			}
+		})
+
+		if err != nil {
+			return err
+		} else if addErr != nil {
+			return addErr
+		}
+	}
+
+	_, err = d.dec.Token()
+	return err
+}
+
+func (d *Decoder[T]) decodeItems(add func(item *T)) error {
+	for d.dec.More() {
+		var item *T
+
+		if err := d.dec.Decode(&item); err != nil {
+			return err
+		}
+
+		add(item)
+	}
+
+	//Closing ']'
+	_, err := d.dec.Token()
+	return err
+}
+
+//Reads the opening delimiter. Reports true if the value is null instead.
+func (d *Decoder[T]) expectDelim(delim json.Delim) (bool, error) {
+	token, err := d.dec.Token()
+	if err != nil {
+		return false, err
+	}
+
+	if token == nil {
+		return true, nil
+	}
+
+	if token != delim {
+		return false, GetErrorf(UnexpectedToken, delim, token)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,297 @@
+package PointerList
+
+import "time"
+
+/////////////////////////////////
+//          Key Limit          //
+/////////////////////////////////
+
+//What Add and Insert do with a tag that is at its limit.
+type LimitPolicy int
+
+const (
+	//The element is not added, Add and Insert return a KeyLimit error.
+	LimitReject LimitPolicy = iota
+	//The first element of the tag is removed to make room.
+	LimitDropOldest
+	//The last element of the tag is removed to make room.
+	LimitDropNewest
+)
+
+//Number of elements of a tag and its limit.
+type KeyCount struct {
+	Count int
+	//0 is unlimited.
+	Limit  int
+	Policy LimitPolicy
+}
+
+type keyLimit struct {
+	limit  int
+	policy LimitPolicy
+}
+
+//Limits the number of elements of the tag, n < 1 removes the limit of the tag so the default limit applies.
+//The limit is checked by Add, Insert, TryAdd and AddWithTTL, not by the lists returned by Get.
+func (l *tagList[T]) SetKeyLimit(key string, n int, policy LimitPolicy) {
+	l.limits = setKeyLimit(l.limits, key, n, policy)
+}
+
+//Limits the number of elements of every tag without a SetKeyLimit, n < 1 removes the limit.
+func (l *tagList[T]) SetDefaultLimit(n int, policy LimitPolicy) {
+	l.defaultLimit = keyLimit{limit: maxInt(n, 0), policy: policy}
+}
+
+//Returns the number of elements and the limit of every tag.
+func (l *tagList[T]) MapCountLimit() map[string]KeyCount {
+	count := make(map[string]KeyCount)
+
+	for key, list := range l.mapList {
+		limit := l.limitOf(key)
+		count[key] = KeyCount{Count: countOf[T](list), Limit: limit.limit, Policy: limit.policy}
+	}
+
+	return count
+}
+
+//Limits the number of elements of the tag, n < 1 removes the limit of the tag so the default limit applies.
+//The limit is checked by Add, Insert, TryAdd and AddWithTTL, not by the lists returned by Get.
+func (l *guardedTagList[T]) SetKeyLimit(key string, n int, policy LimitPolicy) {
+	l.lock()
+	defer l.unlock()
+
+	l.limits = setKeyLimit(l.limits, key, n, policy)
+}
+
+//Limits the number of elements of every tag without a SetKeyLimit, n < 1 removes the limit.
+func (l *guardedTagList[T]) SetDefaultLimit(n int, policy LimitPolicy) {
+	l.lock()
+	defer l.unlock()
+
+	l.defaultLimit = keyLimit{limit: maxInt(n, 0), policy: policy}
+}
+
+//Returns the number of elements and the limit of every tag.
+func (l *guardedTagList[T]) MapCountLimit() map[string]KeyCount {
+	l.lock()
+	defer l.unlock()
+
+	count := make(map[string]KeyCount)
+
+	for key, list := range l.mapList {
+		limit := l.limitOf(key)
+		count[key] = KeyCount{Count: countOf[T](list), Limit: limit.limit, Policy: limit.policy}
+	}
+
+	return count
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func (l *tagList[T]) limitOf(key string) keyLimit {
+	if limit, ok := l.limits[key]; ok {
+		return limit
+	}
+
+	return l.defaultLimit
+}
+
+func (l *guardedTagList[T]) limitOf(key string) keyLimit {
+	if limit, ok := l.limits[key]; ok {
+		return limit
+	}
+
+	return l.defaultLimit
+}
+
+//Returns the index of the element removed to make room in the tag, or -1. The GuardedTagList is locked.
+func (l *guardedTagList[T]) roomIndex(key string) (int, error) {
+	l.lock()
+	defer l.unlock()
+
+	return l.limitOf(key).roomIndex(key, countOf[T](l.mapList[key]))
+}
+
+//Returns the error of a Unique index or of the set of the tag that rejects the element.
+func (l *guardedTagList[T]) rejects(key string, value *T) error {
+	l.lock()
+	defer l.unlock()
+
+	if list := l.mapList[key]; list != nil {
+		return list.(listInternals[T]).rejects(value)
+	}
+
+	return nil
+}
+
+//Same as indexRejects under the list lock.
+func (l *pointerList[T]) rejects(item *T) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.indexRejects(item)
+}
+
+//Returns a KeyLimit error if the elements counted by tag do not fit a tag with LimitReject.
+func (l *tagList[T]) fits(counts map[string]int) error {
+	for key, n := range counts {
+		if err := l.limitOf(key).rejectsMany(key, countOf[T](l.mapList[key]), n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//Returns a KeyLimit error if the elements counted by tag do not fit a tag with LimitReject.
+func (l *guardedTagList[T]) fits(counts map[string]int) error {
+	l.lock()
+	defer l.unlock()
+
+	for key, n := range counts {
+		if err := l.limitOf(key).rejectsMany(key, countOf[T](l.mapList[key]), n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setKeyLimit(limits map[string]keyLimit, key string, n int, policy LimitPolicy) map[string]keyLimit {
+	if n < 1 {
+		delete(limits, key)
+		return limits
+	}
+
+	if limits == nil {
+		limits = make(map[string]keyLimit)
+	}
+
+	limits[key] = keyLimit{limit: n, policy: policy}
+
+	return limits
+}
+
+//Returns the index of the element removed to make room for one more, or -1 if the tag has room.
+func (k keyLimit) roomIndex(key string, count int) (int, error) {
+	if k.limit < 1 || count < k.limit {
+		return -1, nil
+	}
+
+	switch k.policy {
+	case LimitDropOldest:
+		return 0, nil
+	case LimitDropNewest:
+		return count - 1, nil
+	default:
+		return -1, GetErrorf(KeyLimit, key, k.limit)
+	}
+}
+
+//Returns a KeyLimit error if one more element does not fit, whatever the policy.
+func (k keyLimit) rejects(key string, count int) error {
+	if k.limit > 0 && count >= k.limit {
+		return GetErrorf(KeyLimit, key, k.limit)
+	}
+
+	return nil
+}
+
+//Returns a KeyLimit error if n more elements are rejected by the policy. The other policies make room for them.
+func (k keyLimit) rejectsMany(key string, count int, n int) error {
+	if k.policy == LimitReject && k.limit > 0 && count+n > k.limit {
+		return GetErrorf(KeyLimit, key, k.limit)
+	}
+
+	return nil
+}
+
+//Inserts the element at the index, or adds it with at < 0, once the list was locked. The elements that make room for
+//it are removed after the limit and the indexes accepted it, so a rejection leaves the list unchanged.
+//Returns the first removed element.
+func (l *pointerList[T]) insertLimited(key string, item *T, limit keyLimit, at int) (*T, error) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.admit(key, item, limit, at)
+}
+
+//Same as insertLimited at the end of the list, removing the element once d passed without a Touch.
+func (l *pointerList[T]) addLimitedWithTTL(key string, item *T, limit keyLimit, d time.Duration) (*T, error) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	evicted, err := l.admit(key, item, limit, -1)
+	if err == nil {
+		l.expiry().schedule(item, d)
+	}
+
+	return evicted, err
+}
+
+//Same as insertLimited at the end of the list, once the list was locked within timeout.
+func (l *pointerList[T]) tryAddLimited(key string, item *T, limit keyLimit, timeout time.Duration) (*T, bool, error) {
+	if l.BASE != nil {
+		if !l.tryStart(timeout) {
+			return nil, false, nil
+		}
+		defer l.end()
+	}
+
+	evicted, err := l.admit(key, item, limit, -1)
+
+	return evicted, err == nil, err
+}
+
+//Checks the element against the limit and the indexes before removing the elements that make room for it. at is
+//moved with the removed elements. The list is locked.
+func (l *pointerList[T]) admit(key string, item *T, limit keyLimit, at int) (*T, error) {
+	if at > len(l.list) {
+		return nil, GetErrorf(IndexOutOfRange, at, len(l.list))
+	} else if _, err := limit.roomIndex(key, len(l.list)); err != nil {
+		return nil, err
+	} else if err := l.indexRejects(item); err != nil {
+		return nil, err
+	}
+
+	var evicted *T
+
+	for index, _ := limit.roomIndex(key, len(l.list)); index >= 0; index, _ = limit.roomIndex(key, len(l.list)) {
+		if evicted == nil {
+			evicted = l.list[index]
+		}
+
+		l.removeAt(index)
+
+		if index < at {
+			at--
+		}
+	}
+
+	if at < 0 {
+		l.list = append(l.list, item)
+		l.indexAdd(item, len(l.list)-1)
+	} else {
+		l.list, _ = sliceInsert(l.list, item, at)
+		l.indexAdd(item, at)
+	}
+
+	return evicted, nil
+}
+
+//Count of a list that may be nil.
+func countOf[T any](list PointerList[T]) int {
+	if list == nil {
+		return 0
+	}
+
+	return list.Count()
+}
@@ -0,0 +1,164 @@
+package PointerList
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+/////////////////////////////////
+//           Parallel          //
+/////////////////////////////////
+
+//Number of indexes a worker takes at once.
+const parallelBatchSize = 64
+
+//Calls f for every element of a snapshot of the PointerList[T] on workers goroutines (GOMAXPROCS if workers <= 0).
+//Stops at the first error, panic or context cancellation and returns that error.
+func ParallelForeach[T any](ctx context.Context, l PointerList[T], workers int, f ParallelForeachListFunc[T]) error {
+	items := snapshotOf[T](l)
+
+	return parallelRun(ctx, len(items), workers, func(index int) error {
+		return f(index, items[index])
+	})
+}
+
+//Retrieves all the elements that match the conditions defined by the specified predicate, in the order of the list.
+func ParallelFindAll[T any](ctx context.Context, l PointerList[T], workers int, f FindPointerFunc[T]) ([]*T, error) {
+	items := snapshotOf[T](l)
+	matched := make([]bool, len(items))
+
+	err := parallelRun(ctx, len(items), workers, func(index int) error {
+		matched[index] = f(index, items[index])
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	retList := make([]*T, 0)
+
+	for i := 0; i < len(items); i++ {
+		if matched[i] {
+			retList = append(retList, items[i])
+		}
+	}
+
+	return retList, nil
+}
+
+//Returns the number of elements that match the conditions defined by the specified predicate.
+func ParallelCount[T any](ctx context.Context, l PointerList[T], workers int, f FindPointerFunc[T]) (int, error) {
+	items := snapshotOf[T](l)
+	var count int64
+
+	err := parallelRun(ctx, len(items), workers, func(index int) error {
+		if f(index, items[index]) {
+			atomic.AddInt64(&count, 1)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+//Projects each element of the PointerList[T] into a new PointerList[U], in the order of the list.
+func ParallelMap[T any, U any](ctx context.Context, l PointerList[T], workers int, f MapPointerFunc[T, U]) (PointerList[U], error) {
+	items := snapshotOf[T](l)
+	retList := make([]*U, len(items))
+
+	err := parallelRun(ctx, len(items), workers, func(index int) error {
+		retList[index] = f(items[index])
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pointerList[U]{
+		list: retList,
+	}, nil
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func parallelRun(ctx context.Context, count int, workers int, f func(index int) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		next     int64
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for runCtx.Err() == nil {
+				start := int(atomic.AddInt64(&next, parallelBatchSize)) - parallelBatchSize
+				if start >= count {
+					return
+				}
+
+				end := start + parallelBatchSize
+				if end > count {
+					end = count
+				}
+
+				for i := start; i < end; i++ {
+					if runCtx.Err() != nil {
+						return
+					}
+
+					if err := parallelCall(f, i); err != nil {
+						fail(err)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return ctx.Err()
+}
+
+//Converts a panic of f into an error.
+func parallelCall(f func(index int) error, index int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = GetErrorf(WorkerPanic, r)
+		}
+	}()
+
+	return f(index)
+}
@@ -1,5 +1,13 @@
 package PointerList
 
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+)
+
 /////////////////////////////////
 //        Pointer List         //
 /////////////////////////////////
@@ -7,6 +15,22 @@ package PointerList
 //List containing only pointer variables
 type PointerList[T any] interface {
 	BASE
+	//Encodes the list as a JSON array, nil elements as null.
+	json.Marshaler
+	//Replaces the elements with a decoded JSON array.
+	json.Unmarshaler
+	//Writes a snapshot of the list using GobCodec[T].
+	io.WriterTo
+	//Replaces the elements with a snapshot written by WriteTo.
+	io.ReaderFrom
+	//Writes the list as CSV with a header row. Without columns, the fields of T are used.
+	ExportCSV(w io.Writer, columns ...Column[T]) error
+	//Adds the rows of a CSV written by ExportCSV. Without build, the columns are parsed into the fields of T.
+	ImportCSV(r io.Reader, build CSVBuildFunc[T]) error
+	//Same as ExportCSV, separated by tabs.
+	ExportTSV(w io.Writer, columns ...Column[T]) error
+	//Same as ImportCSV, separated by tabs.
+	ImportTSV(r io.Reader, build CSVBuildFunc[T]) error
 
 	ToArray() []*T
 	//Returns the number of elements in a sequence.
@@ -22,6 +46,10 @@ type PointerList[T any] interface {
 	Add(item *T)
 	//Adds the elements of the specified collection to the end of the PointerList[T].
 	AddRange(item []*T)
+	//Adds an object to the end of the PointerList[T]. Returns the error of the Unique index or PointerSet that rejected it.
+	AddChecked(item *T) error
+	//Adds the elements of the specified collection to the end of the PointerList[T]. Nothing is added if one is rejected.
+	AddRangeChecked(items []*T) error
 	//Removes the first occurrence of a specific object from the PointerList[T].
 	Remove(targetItem *T) bool
 	//Removes the element at the specified index of the PointerList[T].
@@ -54,6 +82,76 @@ type PointerList[T any] interface {
 	FindAndRemove(f FindPointerFunc[T]) *T
 	//Loop
 	Foreach(f ForeachListFunc[T])
+
+	//Determines whether an element is in the PointerList[T]. Returns ctx.Err() if the context is done.
+	ContainsCtx(ctx context.Context, targetItem *T) (bool, error)
+	//Searches for an element that matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+	FindCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error)
+	//Retrieves all the elements that match the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+	FindAllCtx(ctx context.Context, f FindPointerFunc[T]) ([]*T, error)
+	//Find and remove. Returns ctx.Err() if the context is done, the list is not changed.
+	FindAndRemoveCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error)
+	//Determines whether every element matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+	TrueForAllCtx(ctx context.Context, f TrueForAllPointerFunc[T]) (bool, error)
+	//Removes all the elements that match the conditions defined by the specified predicate. Returns ctx.Err() if the context is done, the list is not changed.
+	RemoveAllCtx(ctx context.Context, f RemovePointerFunc[T]) error
+	//Sorts the elements using the specified SortPointerFunc[T]. Returns ctx.Err() if the context is done, the list is not changed.
+	SortCtx(ctx context.Context, f SortPointerFunc[T]) error
+	//Reverses the order of the elements. Returns ctx.Err() if the context is done, the list is not changed.
+	ReverseCtx(ctx context.Context) error
+	//Removes the first occurrence of a specific object. Returns ctx.Err() if the context is done, the list is not changed.
+	RemoveCtx(ctx context.Context, targetItem *T) (bool, error)
+	//Inserts the elements of a collection at the specified index. Returns ctx.Err() if the context is done, the list is not changed.
+	InsertRangeCtx(ctx context.Context, targetItems []*T, targetIndex int) error
+	//Inserts an element at the specified index. Returns ctx.Err() if the context is done, the list is not changed.
+	InsertCtx(ctx context.Context, targetItem *T, targetIndex int) error
+	//Removes the element at the specified index. Returns ctx.Err() if the context is done, the list is not changed.
+	RemoveAtCtx(ctx context.Context, index int) (bool, error)
+	//Adds the elements of a collection to the end. Returns the error of a Unique index or of a PointerSet that rejected
+	//one of them, or ctx.Err() if the context is done, the list is not changed then.
+	AddRangeCtx(ctx context.Context, items []*T) error
+	//Loop. Returns ctx.Err() if the context is done.
+	ForeachCtx(ctx context.Context, f ForeachListFunc[T]) error
+
+	//Creates or replaces the named index over the elements, maintained by every change of the list. Nil elements are not indexed.
+	CreateIndex(name string, keyFn IndexKeyFunc[T], options ...IndexOption) error
+	//Removes the named index.
+	DropIndex(name string) bool
+	//Returns the elements with the specified key in the named index, in no particular order.
+	Lookup(name string, key any) []*T
+	//Recomputes the keys of an element after it was changed.
+	Reindex(item *T) error
+
+	//Adds an object to the end of the PointerList[T] that is removed once d passed without a Touch.
+	AddWithTTL(item *T, d time.Duration) error
+	//Restarts the time-to-live of an element added by AddWithTTL. Returns false if it has none.
+	Touch(item *T) bool
+	//Sets the func called with every expired element, after the list was released.
+	OnExpire(f ExpireFunc[T])
+
+	//Reverts the last step of WithHistory. Returns false if there is none or the list has no history.
+	Undo() bool
+	//Applies the last reverted step again. Returns false if there is none or the list has no history.
+	Redo() bool
+	//Determines whether there is a step to Undo.
+	CanUndo() bool
+	//Determines whether there is a step to Redo.
+	CanRedo() bool
+	//Names the current state of the list for RevertTo.
+	Checkpoint(name string)
+	//Undoes or redoes steps until the list is in the state named by Checkpoint.
+	RevertTo(name string) error
+	//Calls f and records the changes it makes as one step.
+	Batch(f func())
+
+	//Returns a number increased by every change of the list.
+	Version() uint64
+	//Replaces the elements if the list is still at version, else returns ErrConflict.
+	ReplaceIfVersion(version uint64, items []*T) error
+	//Replaces the element at the specified index if the list is still at version, else returns ErrConflict.
+	SetIfVersion(version uint64, index int, item *T) error
+	//Adds an object to the end of the PointerList[T] if the list is still at version, else returns ErrConflict.
+	AddIfVersion(version uint64, item *T) error
 	//Capacity() //TODO: ...
 }
 
@@ -61,12 +159,20 @@ type pointerList[T any] struct {
 	BASE
 	list      []*T
 	lastIndex int
+	indexes   map[string]*listIndex[T]
+	positions *setPositions[T]
+	equal     EqualPointerFunc[T]
+	keyOf     IndexKeyFunc[T]
+	keyIndex  *listIndex[T]
+	clock     Clock
+	ttl       *listTTL[T]
+	history   *listHistory[T]
+	hooks     baseHooks
+	version   uint64
 }
 
-func NewPointerList[T any]() PointerList[T] {
-	return &pointerList[T]{
-		list: make([]*T, 0),
-	}
+func NewPointerList[T any](options ...ListOption[T]) PointerList[T] {
+	return newPointerList(make([]*T, 0), nil, options)
 }
 
 //Gets the element at the specified index.
@@ -85,8 +191,8 @@ func (l *pointerList[T]) Get(index int) *T {
 
 func (l *pointerList[T]) GetNext() *T {
 	if l.BASE != nil {
-		l.start()
-		defer l.end()
+		l.startRead()
+		defer l.endRead()
 	}
 
 	return l.getNext()
@@ -102,24 +208,78 @@ func (l *pointerList[T]) Count() int {
 	return len(l.list)
 }
 
-//Adds an object to the end of the PointerList[T].
+//Adds an object to the end of the PointerList[T]. An element rejected by a Unique index is not added.
 func (l *pointerList[T]) Add(item *T) {
 	if l.BASE != nil {
 		l.start()
 		defer l.end()
 	}
 
+	if l.indexRejects(item) != nil {
+		return
+	}
+
 	l.list = append(l.list, item)
+	l.indexAdd(item, len(l.list)-1)
 }
 
-//Adds the elements of the specified collection to the end of the PointerList[T].
+//Adds the elements of the specified collection to the end of the PointerList[T]. Elements rejected by a Unique index are not added.
 func (l *pointerList[T]) AddRange(item []*T) {
 	if l.BASE != nil {
 		l.start()
 		defer l.end()
 	}
 
-	l.list = append(l.list, item...)
+	if !l.indexed() {
+		l.list = append(l.list, item...)
+		l.version++
+		return
+	}
+
+	for i := 0; i < len(item); i++ {
+		if l.indexRejects(item[i]) == nil {
+			l.list = append(l.list, item[i])
+			l.indexAdd(item[i], len(l.list)-1)
+		}
+	}
+}
+
+//Adds an object to the end of the PointerList[T]. Returns the DuplicateKey error of a Unique index or the
+//DuplicateItem error of a PointerSet that rejected the element, the list is not changed.
+func (l *pointerList[T]) AddChecked(item *T) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if err := l.indexRejects(item); err != nil {
+		return err
+	}
+
+	l.list = append(l.list, item)
+	l.indexAdd(item, len(l.list)-1)
+
+	return nil
+}
+
+//Adds the elements of the specified collection to the end of the PointerList[T]. Returns the error of the first
+//element rejected by a Unique index or a PointerSet, including duplicates among items, nothing is added then.
+func (l *pointerList[T]) AddRangeChecked(items []*T) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if err := l.indexRejectsRange(items); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(items); i++ {
+		l.list = append(l.list, items[i])
+		l.indexAdd(items[i], len(l.list)-1)
+	}
+
+	return nil
 }
 
 //Removes the first occurrence of a specific object from the PointerList[T].
@@ -159,12 +319,7 @@ func (l *pointerList[T]) RemoveAll(f RemovePointerFunc[T]) {
 		defer l.end()
 	}
 
-	for i, i2 := 0, 0; i < len(l.list); i, i2 = i+1, i2+1 {
-		if f(l.list[i], i2) {
-			l.list = append(l.list[:i], l.list[i+1:]...)
-			i--
-		}
-	}
+	l.list = sliceRemoveAll(l.list, f, l.indexRemove)
 }
 
 func (l *pointerList[T]) ToArray() []*T {
@@ -183,23 +338,19 @@ func (l *pointerList[T]) Clear() {
 		defer l.end()
 	}
 
+	before := l.list
 	l.list = make([]*T, 0)
+	l.reindexAll(before)
 }
 
 //Determines whether an element is in the PointerList[T].
 func (l *pointerList[T]) Contains(targetItem *T) bool {
 	if l.BASE != nil {
-		l.start()
-		defer l.end()
-	}
-
-	for i := 0; i < len(l.list); i++ {
-		if l.list[i] == targetItem {
-			return true
-		}
+		l.startRead()
+		defer l.endRead()
 	}
 
-	return false
+	return l.has(targetItem)
 }
 
 //Inserts an element into the PointerList[T] at the specified index.
@@ -211,20 +362,12 @@ func (l *pointerList[T]) Insert(targetItem *T, targetIndex int) error {
 
 	if targetIndex > len(l.list) || targetIndex < 0 {
 		return GetErrorf(IndexOutOfRange, len(l.list), len(l.list))
+	} else if err := l.indexRejects(targetItem); err != nil {
+		return err
 	}
 
-	newArray := make([]*T, len(l.list)+1)
-
-	for i, index := 0, 0; i < len(newArray); i, index = i+1, index+1 {
-		if i == targetIndex {
-			newArray[i] = targetItem
-			index--
-		} else {
-			newArray[i] = l.list[index]
-		}
-	}
-
-	l.list = newArray
+	l.list, _ = sliceInsert(l.list, targetItem, targetIndex)
+	l.indexAdd(targetItem, targetIndex)
 
 	return nil
 }
@@ -240,25 +383,16 @@ func (l *pointerList[T]) InsertRange(targetItems []*T, targetIndex int) error {
 		return GetErrorf(IndexOutOfRange, len(l.list), len(l.list))
 	} else if len(targetItems) == 0 {
 		return nil
+	} else if err := l.indexRejectsRange(targetItems); err != nil {
+		return err
 	}
 
-	newArray := make([]*T, len(l.list)+len(targetItems))
-
-	for i, index := 0, 0; i < len(newArray); i, index = i+1, index+1 {
-		if i == targetIndex {
-			for i2 := 0; i2 < len(targetItems); i2++ {
-				newArray[i+i2] = targetItems[i2]
-			}
-			i += len(targetItems) - 1
+	l.list, _ = sliceInsertRange(l.list, targetItems, targetIndex)
 
-			index--
-		} else {
-			newArray[i] = l.list[index]
-		}
+	for i := 0; i < len(targetItems); i++ {
+		l.indexAdd(targetItems[i], targetIndex+i)
 	}
 
-	l.list = newArray
-
 	return nil
 }
 
@@ -273,9 +407,9 @@ func (l *pointerList[T]) Reverse() {
 		return
 	}
 
-	for i, j := 0, len(l.list)-1; i < j; i, j = i+1, j-1 {
-		l.list[i], l.list[j] = l.list[j], l.list[i]
-	}
+	before := l.historyBefore()
+	sliceReverse(l.list)
+	l.indexMoved(before)
 }
 
 //Sorts the elements in the entire PointerList[T] using the specified SortPointerFunc[T].
@@ -285,16 +419,9 @@ func (l *pointerList[T]) Sort(f SortPointerFunc[T]) {
 		defer l.end()
 	}
 
-	for i2 := 0; i2 < len(l.list); i2++ {
-		for i := 0; i < len(l.list); i++ {
-			if f(l.list[i], l.list[i2]) {
-				l.list[i], l.list[i2] = l.list[i2], l.list[i]
-			}
-			/*if l.list[i] > l.list[i2] {
-				l.list[i], l.list[i2] = l.list[i2], l.list[i]
-			}*/
-		}
-	}
+	before := l.historyBefore()
+	sliceSort(l.list, f)
+	l.indexMoved(before)
 }
 
 //Searches for an element that matches the conditions defined by the specified predicate, and returns the first occurrence within the entire PointerList[T].
@@ -304,10 +431,8 @@ func (l *pointerList[T]) Find(f FindPointerFunc[T]) *T {
 		defer l.end()
 	}
 
-	for i := 0; i < len(l.list); i++ {
-		if f(i, l.list[i]) {
-			return l.list[i]
-		}
+	if index := sliceFindIndex(l.list, f); index >= 0 {
+		return l.list[index]
 	}
 
 	return nil
@@ -320,122 +445,535 @@ func (l *pointerList[T]) FindAll(f FindPointerFunc[T]) []*T {
 		defer l.end()
 	}
 
+	return sliceFindAll(l.list, f)
+}
+
+//Determines whether every element in the PointerList[T] matches the conditions defined by the specified predicate.
+func (l *pointerList[T]) TrueForAll(f TrueForAllPointerFunc[T]) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return sliceTrueForAll(l.list, f)
+}
+
+//Find and remove
+func (l *pointerList[T]) FindAndRemove(f FindPointerFunc[T]) *T {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if index := sliceFindIndex(l.list, f); index >= 0 {
+		target := l.list[index]
+		l.removeAt(index)
+		return target
+	}
+
+	return nil
+}
+
+func (l *pointerList[T]) GetNextBefore(f BeforeListFunc[T]) *T {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
+	}
+
+	for i := 0; i < len(l.list); i++ { //l.mapList[key].Count() => MaxCount
+		currentItem := l.getNext()
+
+		if currentItem == nil {
+			break
+		}
+
+		if f(currentItem) {
+			return currentItem
+		}
+	}
+
+	return nil
+}
+
+func (l *pointerList[T]) Foreach(f ForeachListFunc[T]) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	sliceForeach(l.list, f)
+}
+
+//Determines whether an element is in the PointerList[T]. Returns ctx.Err() if the context is done.
+func (l *pointerList[T]) ContainsCtx(ctx context.Context, targetItem *T) (bool, error) {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
+	}
+
+	if l.keyIndex != nil || (l.positions != nil && !l.byValue()) {
+		return l.has(targetItem), nil
+	}
+
+	for i := 0; i < len(l.list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return false, err
+		}
+
+		if l.same(l.list[i], targetItem) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+func (l *pointerList[T]) FindCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	index, err := l.findIndexCtx(ctx, f)
+	if err != nil || index < 0 {
+		return nil, err
+	}
+
+	return l.list[index], nil
+}
+
+//Retrieves all the elements that match the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+func (l *pointerList[T]) FindAllCtx(ctx context.Context, f FindPointerFunc[T]) ([]*T, error) {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
+	}
+
 	retList := make([]*T, 0)
 
 	for i := 0; i < len(l.list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return nil, err
+		}
+
 		if f(i, l.list[i]) {
 			retList = append(retList, l.list[i])
 		}
 	}
 
-	return retList
+	return retList, nil
 }
 
-//Determines whether every element in the PointerList[T] matches the conditions defined by the specified predicate.
-func (l *pointerList[T]) TrueForAll(f TrueForAllPointerFunc[T]) bool {
+//Find and remove. Returns ctx.Err() if the context is done, the list is not changed.
+func (l *pointerList[T]) FindAndRemoveCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error) {
 	if l.BASE != nil {
 		l.start()
 		defer l.end()
 	}
 
+	index, err := l.findIndexCtx(ctx, f)
+	if err != nil || index < 0 {
+		return nil, err
+	}
+
+	target := l.list[index]
+	l.removeAt(index)
+
+	return target, nil
+}
+
+//Determines whether every element matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+func (l *pointerList[T]) TrueForAllCtx(ctx context.Context, f TrueForAllPointerFunc[T]) (bool, error) {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
+	}
+
 	for i := 0; i < len(l.list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return false, err
+		}
+
 		if !f(l.list[i]) {
-			return false
+			return false, nil
 		}
 	}
 
-	return true
+	return true, nil
 }
 
-//Find and remove
-func (l *pointerList[T]) FindAndRemove(f FindPointerFunc[T]) *T {
+//Removes all the elements that match the conditions defined by the specified predicate. Returns ctx.Err() if the context is done, the list is not changed.
+func (l *pointerList[T]) RemoveAllCtx(ctx context.Context, f RemovePointerFunc[T]) error {
 	if l.BASE != nil {
 		l.start()
 		defer l.end()
 	}
 
+	//The remaining elements are collected into a new array, which replaces the list only when the walk completed.
+	newArray := make([]*T, 0, len(l.list))
+
 	for i := 0; i < len(l.list); i++ {
-		if f(i, l.list[i]) {
-			target := l.list[i]
-			l.removeAt(i)
-			return target
+		if err := checkCtx(ctx, i); err != nil {
+			return err
+		}
+
+		if !f(l.list[i], i) {
+			newArray = append(newArray, l.list[i])
 		}
 	}
 
+	before := l.list
+	l.list = newArray
+	l.reindexAll(before)
+
 	return nil
 }
 
-func (l *pointerList[T]) GetNextBefore(f BeforeListFunc[T]) *T {
+//Sorts the elements using the specified SortPointerFunc[T]. Returns ctx.Err() if the context is done, the list is not changed.
+func (l *pointerList[T]) SortCtx(ctx context.Context, f SortPointerFunc[T]) error {
 	if l.BASE != nil {
 		l.start()
 		defer l.end()
 	}
 
-	for i := 0; i < len(l.list); i++ { //l.mapList[key].Count() => MaxCount
-		currentItem := l.getNext()
+	newArray := cloneArray(l.list)
 
-		if currentItem == nil {
-			break
-		}
+	if err := sliceSortCtx(ctx, newArray, f); err != nil {
+		return err
+	}
 
-		if f(currentItem) {
-			return currentItem
+	before := l.list
+	l.list = newArray
+	l.indexMoved(before)
+
+	return nil
+}
+
+//Reverses the order of the elements. Returns ctx.Err() if the context is done, the list is not changed.
+func (l *pointerList[T]) ReverseCtx(ctx context.Context) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if len(l.list) == 0 {
+		return nil
+	}
+
+	newArray := make([]*T, len(l.list))
+
+	for i := 0; i < len(l.list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return err
 		}
+
+		newArray[len(l.list)-1-i] = l.list[i]
 	}
 
+	before := l.list
+	l.list = newArray
+	l.indexMoved(before)
+
 	return nil
 }
 
-func (l *pointerList[T]) Foreach(f ForeachListFunc[T]) {
+//Removes the first occurrence of a specific object. Returns ctx.Err() if the context is done, the list is not changed.
+func (l *pointerList[T]) RemoveCtx(ctx context.Context, targetItem *T) (bool, error) {
 	if l.BASE != nil {
 		l.start()
 		defer l.end()
 	}
 
+	if l.keyIndex != nil || (l.positions != nil && !l.byValue()) {
+		return l.remove(targetItem), nil
+	}
+
 	for i := 0; i < len(l.list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return false, err
+		}
+
+		if l.same(l.list[i], targetItem) {
+			return l.removeAt(i), nil
+		}
+	}
+
+	return false, nil
+}
+
+//Inserts the elements of a collection at the specified index. Returns ctx.Err() if the context is done, the list is not changed.
+func (l *pointerList[T]) InsertRangeCtx(ctx context.Context, targetItems []*T, targetIndex int) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.insertRangeCtx(ctx, targetItems, targetIndex)
+}
+
+//Inserts an element at the specified index. Returns ctx.Err() if the context is done, the list is not changed.
+func (l *pointerList[T]) InsertCtx(ctx context.Context, targetItem *T, targetIndex int) error {
+	return l.InsertRangeCtx(ctx, []*T{targetItem}, targetIndex)
+}
+
+//Adds the elements of a collection to the end. Unlike AddRange, an element rejected by a Unique index or a PointerSet
+//returns its error. Returns ctx.Err() if the context is done, the list is not changed then.
+func (l *pointerList[T]) AddRangeCtx(ctx context.Context, items []*T) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.insertRangeCtx(ctx, items, len(l.list))
+}
+
+//Removes the element at the specified index. Returns ctx.Err() if the context is done, the list is not changed.
+func (l *pointerList[T]) RemoveAtCtx(ctx context.Context, index int) (bool, error) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if index >= len(l.list) || index < 0 {
+		return false, nil
+	}
+
+	//The elements after the index are copied into a new array, which replaces the list only when the copy completed.
+	newArray := make([]*T, index, len(l.list)-1)
+	copy(newArray, l.list[:index])
+
+	for i := index + 1; i < len(l.list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return false, err
+		}
+
+		newArray = append(newArray, l.list[i])
+	}
+
+	l.indexRemove(l.list[index], index)
+	l.list = newArray
+
+	return true, nil
+}
+
+//Loop. Returns ctx.Err() if the context is done.
+func (l *pointerList[T]) ForeachCtx(ctx context.Context, f ForeachListFunc[T]) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	for i := 0; i < len(l.list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return err
+		}
+
 		if !f(i, l.list[i]) {
 			break
 		}
 	}
+
+	return nil
 }
 
 /////////////////////////////////
 //            PRIVATE          //
 /////////////////////////////////
 
+//Same as InsertRangeCtx, the list is locked.
+func (l *pointerList[T]) insertRangeCtx(ctx context.Context, targetItems []*T, targetIndex int) error {
+	if targetIndex > len(l.list) || targetIndex < 0 {
+		return GetErrorf(IndexOutOfRange, len(l.list), len(l.list))
+	} else if len(targetItems) == 0 {
+		return nil
+	} else if err := l.indexRejectsRange(targetItems); err != nil {
+		return err
+	}
+
+	//The elements are copied into a new array, which replaces the list only when the copy completed.
+	count := len(l.list) + len(targetItems)
+	newArray := make([]*T, 0, count)
+
+	for i := 0; i < count; i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return err
+		}
+
+		switch {
+		case i < targetIndex:
+			newArray = append(newArray, l.list[i])
+		case i < targetIndex+len(targetItems):
+			newArray = append(newArray, targetItems[i-targetIndex])
+		default:
+			newArray = append(newArray, l.list[i-len(targetItems)])
+		}
+	}
+
+	l.list = newArray
+
+	for i := 0; i < len(targetItems); i++ {
+		l.indexAdd(targetItems[i], targetIndex+i)
+	}
+
+	return nil
+}
+
+//Returns the index of the first element that matches the predicate, or -1.
+func (l *pointerList[T]) findIndexCtx(ctx context.Context, f FindPointerFunc[T]) (int, error) {
+	for i := 0; i < len(l.list); i++ {
+		if err := checkCtx(ctx, i); err != nil {
+			return -1, err
+		}
+
+		if f(i, l.list[i]) {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+//Takes the BASE within timeout. A BASE without tryStart is waited for.
+func (l *pointerList[T]) tryStart(timeout time.Duration) bool {
+	return tryStartBase(l.BASE, timeout)
+}
+
 //Removes the element at the specified index of the PointerList[T].
 func (l *pointerList[T]) removeAt(index int) bool {
 	if index >= len(l.list) || index < 0 {
 		return false
 	}
 
-	l.list = append(l.list[:index], l.list[index+1:]...)
+	l.indexRemove(l.list[index], index)
+	l.list, _ = sliceRemoveAt(l.list, index)
 	return true
 }
 
 //Removes the first occurrence of a specific object from the PointerList[T].
 func (l *pointerList[T]) remove(targetItem *T) bool {
-	for i := 0; i < len(l.list); i++ {
-		if l.list[i] == targetItem {
-			l.list = append(l.list[:i], l.list[i+1:]...)
-			return true
-		}
+	return l.removeAt(l.find(targetItem))
+}
+
+//Methods of the lists of this package that the package level helpers use, found by a type assertion so that
+//PointerList[T] can be implemented outside the package.
+type listInternals[T any] interface {
+	//Copies the elements under the list lock.
+	snapshot() []*T
+	//Replaces the elements under the list lock.
+	replace(items []*T)
+	//Removes the expired elements.
+	purge()
+	//Creates the named index, keyType is the type of the keys of CreateIndex[T, K] or nil.
+	createIndex(name string, keyFn IndexKeyFunc[T], keyType reflect.Type, options []IndexOption) error
+	//Returns the error of a Unique index or of the set that rejects the element.
+	rejects(item *T) error
+	//Inserts the element, removing the elements that make room for it within the limit of the tag once it is certain to be added.
+	insertLimited(key string, item *T, limit keyLimit, at int) (*T, error)
+	//Same as insertLimited with AddWithTTL.
+	addLimitedWithTTL(key string, item *T, limit keyLimit, d time.Duration) (*T, error)
+	//Same as insertLimited with TryAdd.
+	tryAddLimited(key string, item *T, limit keyLimit, timeout time.Duration) (*T, bool, error)
+}
+
+//Copies the elements of the list, with ToArray for a list implemented outside the package.
+func snapshotOf[T any](l PointerList[T]) []*T {
+	if list, ok := l.(listInternals[T]); ok {
+		return list.snapshot()
 	}
 
-	return false
+	return cloneArray(l.ToArray())
 }
 
-func (l *pointerList[T]) getNext() *T {
-	if len(l.list) == 0 {
-		return nil
+//Replaces the elements of the list, with Clear and AddRange for a list implemented outside the package.
+func replaceOf[T any](l PointerList[T], items []*T) {
+	if list, ok := l.(listInternals[T]); ok {
+		list.replace(items)
+		return
+	}
+
+	l.Clear()
+	l.AddRange(items)
+}
+
+//Removes the expired elements of a list of this package.
+func purgeOf[T any](l PointerList[T]) {
+	if list, ok := l.(listInternals[T]); ok {
+		list.purge()
+	}
+}
+
+//Takes the BASE for a method that only reads the list, see readBASE.
+func (l *pointerList[T]) startRead() {
+	if base, ok := l.BASE.(readBASE); ok {
+		base.startRead()
+	} else {
+		l.start()
 	}
+}
+
+func (l *pointerList[T]) endRead() {
+	if base, ok := l.BASE.(readBASE); ok {
+		base.endRead()
+	} else {
+		l.end()
+	}
+}
 
-	if l.lastIndex >= len(l.list) {
-		l.lastIndex = 0
+//Copies the elements under the list lock.
+func (l *pointerList[T]) snapshot() []*T {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
 	}
 
-	selectedItem := l.list[l.lastIndex]
-	l.lastIndex++
+	items := make([]*T, len(l.list))
+	copy(items, l.list)
+
+	return items
+}
+
+//Installs the hook on the BASE next to the hooks installed before. An unguarded list gets a BASE for the hooks only.
+func (l *pointerList[T]) addHook(hook baseHook) {
+	if l.BASE == nil {
+		l.BASE = &hookBase{}
+	}
+
+	l.hooks = append(l.hooks, hook)
+
+	if base, ok := l.BASE.(hookedBASE); ok {
+		if len(l.hooks) == 1 {
+			base.setHook(hook)
+		} else {
+			base.setHook(l.hooks)
+		}
+	}
+}
+
+//Replaces the elements under the list lock.
+func (l *pointerList[T]) replace(items []*T) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	l.replaceItems(items)
+}
+
+//Replaces the elements, the list is locked. A set keeps the first of equal elements.
+func (l *pointerList[T]) replaceItems(items []*T) {
+	if l.positions != nil {
+		items = l.distinct(items)
+	}
+
+	before := l.list
+	l.list = items
+	l.lastIndex = 0
+	l.reindexAll(before)
+}
+
+func (l *pointerList[T]) getNext() *T {
+	selectedItem, _ := sliceGetNext(l.list, &l.lastIndex)
 
 	return selectedItem
 }
@@ -1,10 +1,14 @@
 package PointerList
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestMutex(t *testing.T) {
@@ -93,3 +97,1376 @@ func TestForeachTagList(t *testing.T) {
 		return true
 	})
 }
+
+func TestContext(t *testing.T) {
+	list := NewGuardedPointerList[int]()
+
+	for i := 0; i < 1000; i++ {
+		newI := i
+		list.Add(&newI)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	visited := 0
+
+	err := list.RemoveAllCtx(ctx, func(current *int, index int) bool {
+		visited++
+		if visited == 500 {
+			cancel()
+		}
+		return true
+	})
+
+	if err != context.Canceled || list.Count() != 1000 {
+		t.Errorf("RemoveAllCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if err := list.SortCtx(ctx, func(left, right *int) bool { return *left < *right }); err != context.Canceled || *list.Get(0) != 0 {
+		t.Errorf("SortCtx => err(%v) [0](%d)", err, *list.Get(0))
+	}
+
+	if err := list.ReverseCtx(ctx); err != context.Canceled || *list.Get(0) != 0 {
+		t.Errorf("ReverseCtx => err(%v) [0](%d)", err, *list.Get(0))
+	}
+
+	if removed, err := list.RemoveCtx(ctx, list.Get(999)); err != context.Canceled || removed || list.Count() != 1000 {
+		t.Errorf("RemoveCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if err := list.InsertRangeCtx(ctx, []*int{list.Get(0)}, 0); err != context.Canceled || list.Count() != 1000 {
+		t.Errorf("InsertRangeCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if err := list.InsertCtx(ctx, list.Get(0), 0); err != context.Canceled || list.Count() != 1000 {
+		t.Errorf("InsertCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if removed, err := list.RemoveAtCtx(ctx, 0); err != context.Canceled || removed || *list.Get(0) != 0 {
+		t.Errorf("RemoveAtCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if err := list.AddRangeCtx(ctx, []*int{list.Get(0)}); err != context.Canceled || list.Count() != 1000 {
+		t.Errorf("AddRangeCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	extra := 1000
+
+	if err := list.AddRangeCtx(context.Background(), []*int{&extra}); err != nil || list.Get(1000) != &extra {
+		t.Errorf("AddRangeCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if removed, err := list.RemoveAtCtx(context.Background(), 0); err != nil || !removed || *list.Get(0) != 1 || list.Count() != 1000 {
+		t.Errorf("RemoveAtCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if err := list.InsertCtx(context.Background(), list.Get(999), 0); err != nil || list.Get(0) != &extra {
+		t.Errorf("InsertCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if !list.RemoveAt(0) || !list.Remove(&extra) {
+		t.Error("Remove => false")
+	}
+
+	zero := 0
+	list.Insert(&zero, 0)
+
+	if err := list.ReverseCtx(context.Background()); err != nil || *list.Get(0) != 999 {
+		t.Errorf("ReverseCtx => err(%v) [0](%d)", err, *list.Get(0))
+	}
+
+	if err := list.InsertRangeCtx(context.Background(), []*int{list.Get(1), list.Get(2)}, 1); err != nil || list.Get(1) != list.Get(3) {
+		t.Errorf("InsertRangeCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	if removed, err := list.RemoveCtx(context.Background(), list.Get(1)); err != nil || !removed || list.Count() != 1001 {
+		t.Errorf("RemoveCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	list.RemoveAt(1)
+	list.Reverse()
+
+	err = list.RemoveAllCtx(context.Background(), func(current *int, index int) bool {
+		return *current%2 == 0
+	})
+
+	if err != nil || list.Count() != 500 || *list.Get(0) != 1 {
+		t.Errorf("RemoveAllCtx => err(%v) count(%d)", err, list.Count())
+	}
+
+	tags := NewGuardedTagList[int]()
+	tags.Add("a", list.Get(0))
+
+	if _, err := tags.CountSelectCtx(ctx, func(key string, index int, current *int) bool { return true }); err != context.Canceled {
+		t.Errorf("CountSelectCtx => err(%v)", err)
+	}
+
+	for i := 0; i < 600; i++ {
+		tags.Add("a", list.Get(i))
+	}
+
+	walk, stop := context.WithCancel(context.Background())
+
+	//The removals before the cancel are not applied.
+	err = tags.ForeachCtx(walk, func(key string, index int, current *int, removeCurItem func()) bool {
+		removeCurItem()
+		if index == 300 {
+			stop()
+		}
+		return true
+	})
+
+	if err != context.Canceled || tags.Get("a").Count() != 601 {
+		t.Errorf("ForeachCtx => err(%v) count(%d)", err, tags.Get("a").Count())
+	}
+
+	err = tags.ForeachCtx(context.Background(), func(key string, index int, current *int, removeCurItem func()) bool {
+		if index%2 == 0 {
+			removeCurItem()
+		}
+		return true
+	})
+
+	if err != nil || tags.Get("a").Count() != 300 || tags.Get("a").Get(0) != list.Get(0) {
+		t.Errorf("ForeachCtx => err(%v) count(%d)", err, tags.Get("a").Count())
+	}
+}
+
+func TestTryLock(t *testing.T) {
+	list := NewGuardedPointerList[int]()
+	value := 1
+	list.Add(&value)
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+
+	//Holds the lock of the list until release is closed.
+	go list.Foreach(func(index int, current *int) bool {
+		close(locked)
+		<-release
+		return false
+	})
+
+	<-locked
+
+	if ok, _ := list.TryAdd(&value, time.Millisecond); ok {
+		t.Errorf("TryAdd => locked list accepted the item")
+	}
+
+	close(release)
+
+	if ok, _ := list.TryAdd(&value, time.Second); !ok {
+		t.Errorf("TryAdd => free list rejected the item")
+	}
+}
+
+type indexPlayer struct {
+	ID   int    `list:"id"`
+	Team string `list:"team"`
+}
+
+func TestIndex(t *testing.T) {
+	list := NewGuardedPointerList[indexPlayer]()
+	ann, bob, cid := &indexPlayer{1, "red"}, &indexPlayer{2, "red"}, &indexPlayer{3, "blue"}
+	list.AddRange([]*indexPlayer{ann, bob})
+
+	if err := list.CreateIndex("id", func(current *indexPlayer) any { return current.ID }, Unique()); err != nil {
+		t.Fatal(err)
+	}
+
+	team, err := FieldKey[indexPlayer]("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := list.CreateIndex("team", team); err != nil {
+		t.Fatal(err)
+	}
+
+	list.Add(cid)
+	list.Add(&indexPlayer{ID: 1})
+
+	if list.Count() != 3 || len(list.Lookup("team", "red")) != 2 || list.Lookup("id", 3)[0] != cid {
+		t.Errorf("Add => count(%d)", list.Count())
+	}
+
+	if err := list.Insert(&indexPlayer{ID: 2}, 0); err == nil {
+		t.Errorf("Insert(duplicate) => %v", err)
+	}
+
+	if err := list.AddChecked(&indexPlayer{ID: 2}); err == nil || list.Count() != 3 {
+		t.Errorf("AddChecked(duplicate) => %v", err)
+	}
+
+	if err := list.AddRangeChecked([]*indexPlayer{{ID: 7}, {ID: 7}}); err == nil || list.Count() != 3 {
+		t.Errorf("AddRangeChecked(duplicate) => %v", err)
+	}
+
+	if ok, err := list.TryAdd(&indexPlayer{ID: 3}, time.Second); ok || err == nil {
+		t.Errorf("TryAdd(duplicate) => %v, %v", ok, err)
+	}
+
+	list.Remove(bob)
+	list.Sort(func(left, right *indexPlayer) bool { return left.ID > right.ID })
+
+	if len(list.Lookup("id", 2)) != 0 || len(list.Lookup("team", "red")) != 1 {
+		t.Errorf("Remove => %v", list.Lookup("team", "red"))
+	}
+
+	cid.Team = "red"
+	if list.Reindex(cid) != nil || len(list.Lookup("team", "red")) != 2 || len(list.Lookup("team", "blue")) != 0 {
+		t.Errorf("Reindex => %v", list.Lookup("team", "red"))
+	}
+
+	cid.ID = 1
+	if list.Reindex(cid) == nil {
+		t.Errorf("Reindex(duplicate) => accepted")
+	}
+
+	list.RemoveAll(func(current *indexPlayer, index int) bool { return current == ann })
+	list.Clear()
+
+	if len(list.Lookup("id", 3)) != 0 || len(list.Lookup("team", "red")) != 0 {
+		t.Errorf("Clear => %v", list.Lookup("team", "red"))
+	}
+
+	type account struct {
+		ID   int64
+		Tags []string
+	}
+
+	accounts := NewPointerList[account]()
+	accounts.Add(&account{ID: 1, Tags: []string{"a"}})
+
+	if err := CreateIndex(accounts, "id", func(current *account) int64 { return current.ID }); err != nil {
+		t.Fatal(err)
+	}
+
+	//A slice can not be hashed, the elements are not indexed.
+	if err := accounts.CreateIndex("tags", func(current *account) any { return current.Tags }); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts.Add(&account{ID: 2, Tags: []string{"b"}})
+
+	if len(accounts.Lookup("id", 1)) != 1 || len(Lookup(accounts, "id", int64(2))) != 1 || len(accounts.Lookup("id", 1.5)) != 0 {
+		t.Errorf("CreateIndex[int64] => %v", accounts.Lookup("id", 1))
+	}
+}
+
+func TestPointerSet(t *testing.T) {
+	values := []int{0, 1, 2, 3, 4}
+	set := NewGuardedPointerSet[int]()
+
+	for i := range values {
+		set.Add(&values[i])
+	}
+
+	set.Add(&values[0])
+
+	added, _ := set.AddIfAbsent(&values[1])
+	tried, err := set.TryAdd(&values[2], time.Second)
+
+	if set.Count() != 5 || added || tried || err == nil {
+		t.Errorf("Add(duplicate) => count(%d)", set.Count())
+	}
+
+	if err := set.InsertRange([]*int{new(int), &values[3]}, 0); err == nil {
+		t.Errorf("InsertRange(duplicate) => accepted")
+	}
+
+	set.Remove(&values[1])
+	extra := 5
+	set.Insert(&extra, 0)
+
+	if set.IndexOf(&values[4]) != 4 || set.IndexOf(&extra) != 0 || set.IndexOf(&values[1]) != -1 || set.Contains(&values[1]) {
+		t.Errorf("IndexOf => %d, %d", set.IndexOf(&values[4]), set.IndexOf(&extra))
+	}
+
+	set.Reverse()
+
+	if set.IndexOf(&values[4]) != 0 || set.IndexOf(&extra) != 4 {
+		t.Errorf("Reverse => %d, %d", set.IndexOf(&values[4]), set.IndexOf(&extra))
+	}
+
+	other := NewPointerList[int]()
+	other.AddRange([]*int{&values[0], &values[1], &values[1]})
+
+	if union := set.Union(other); union.Count() != 6 || union.IndexOf(&values[1]) != 5 {
+		t.Errorf("Union => count(%d)", union.Count())
+	}
+
+	if intersect := set.Intersect(other); intersect.Count() != 1 || intersect.Get(0) != &values[0] {
+		t.Errorf("Intersect => count(%d)", intersect.Count())
+	}
+
+	if except := set.Except(other); except.Count() != 4 || except.Contains(&values[0]) {
+		t.Errorf("Except => count(%d)", except.Count())
+	}
+
+	if set.IsSubsetOf(other) || !set.Intersect(other).IsSubsetOf(other) {
+		t.Errorf("IsSubsetOf => wrong result")
+	}
+
+	//Removing from the front renumbers the elements up to the one looked up.
+	front := NewPointerSet[int]()
+	numbers := make([]int, 100)
+
+	for i := range numbers {
+		front.Add(&numbers[i])
+	}
+
+	for i := 0; i < 50; i++ {
+		front.Remove(&numbers[i])
+	}
+
+	if front.IndexOf(&numbers[50]) != 0 || front.IndexOf(&numbers[99]) != 49 || front.IndexOf(&numbers[75]) != 25 {
+		t.Errorf("Remove(front) => %d, %d", front.IndexOf(&numbers[50]), front.IndexOf(&numbers[99]))
+	}
+}
+
+func TestEquality(t *testing.T) {
+	byID := WithKey(func(current *indexPlayer) int { return current.ID })
+	list := NewGuardedPointerList(byID)
+	list.AddRange([]*indexPlayer{{1, "red"}, {2, "red"}, nil})
+
+	if !list.Contains(&indexPlayer{ID: 2}) || list.Contains(&indexPlayer{ID: 3}) || !list.Contains(nil) {
+		t.Errorf("Contains => identity semantics")
+	}
+
+	if !list.Remove(&indexPlayer{ID: 1}) || list.Count() != 2 || list.Contains(&indexPlayer{ID: 1}) {
+		t.Errorf("Remove => count(%d)", list.Count())
+	}
+
+	sameTeam := WithEquality(func(left, right *indexPlayer) bool { return left.Team == right.Team })
+	set := NewPointerSet(sameTeam)
+	set.AddRange([]*indexPlayer{{1, "red"}, {2, "red"}, {3, "blue"}})
+
+	if set.Count() != 2 || set.IndexOf(&indexPlayer{Team: "blue"}) != 1 {
+		t.Errorf("AddRange => count(%d)", set.Count())
+	}
+
+	//{2, "red"} is equal to {1, "red"}, only nil is added.
+	if union := set.Union(list); union.Count() != 3 || union.Get(2) != nil {
+		t.Errorf("Union => count(%d)", union.Count())
+	}
+
+	tags := NewTagList(byID)
+	tags.Add("red", &indexPlayer{ID: 4})
+
+	if !tags.Contains("red", &indexPlayer{ID: 4}) || !tags.Remove("red", &indexPlayer{ID: 4}) {
+		t.Errorf("TagList.Contains => identity semantics")
+	}
+}
+
+func TestDeque(t *testing.T) {
+	values := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	deque := NewGuardedDeque[int]()
+
+	for i := 5; i < 10; i++ {
+		deque.PushBack(&values[i])
+	}
+
+	for i := 4; i >= 0; i-- {
+		deque.PushFront(&values[i])
+	}
+
+	if deque.Count() != 10 || *deque.PeekFront() != 0 || *deque.PeekBack() != 9 || *deque.Get(4) != 4 {
+		t.Errorf("Push => count(%d)", deque.Count())
+	}
+
+	if *deque.PopFront() != 0 || *deque.PopBack() != 9 || *deque.PopFront() != 1 {
+		t.Errorf("Pop => wrong order")
+	}
+
+	//The ring is wrapped around now, the PointerList[T] methods must see it in order.
+	deque.PushFront(&values[1])
+	deque.Insert(&values[0], 0)
+	deque.RemoveAt(5)
+
+	items := deque.ToArray()
+	if len(items) != 8 || *items[0] != 0 || *items[4] != 4 || *items[5] != 6 || *items[7] != 8 {
+		t.Errorf("ToArray => %d", len(items))
+	}
+
+	for deque.PopBack() != nil {
+	}
+
+	if deque.Count() != 0 || deque.PeekFront() != nil || deque.PopFront() != nil {
+		t.Errorf("PopBack => count(%d)", deque.Count())
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	values := []int{0, 1, 2, 3, 4, 5}
+	ring := NewRingBuffer[int](3)
+
+	for i := range values {
+		ring.Add(&values[i])
+	}
+
+	if ring.Count() != 3 || *ring.PeekFront() != 3 || *ring.PeekBack() != 5 {
+		t.Errorf("Add => count(%d)", ring.Count())
+	}
+
+	ring.PushBack(&values[0])
+	ring.PushFront(&values[1])
+
+	if ring.Count() != 3 || *ring.Get(0) != 1 || *ring.Get(1) != 4 || *ring.Get(2) != 5 || ring.Capacity() != 3 {
+		t.Errorf("PushFront => %d, %d, %d", *ring.Get(0), *ring.Get(1), *ring.Get(2))
+	}
+
+	ring.AddRange([]*int{&values[2], &values[3]})
+
+	if ring.Count() != 3 || *ring.PopFront() != 5 || *ring.PopFront() != 2 || *ring.PopFront() != 3 {
+		t.Errorf("AddRange => count(%d)", ring.Count())
+	}
+}
+
+func TestPriorityList(t *testing.T) {
+	values := []int{5, 3, 8, 1, 9, 2}
+	list := NewGuardedPriorityList(func(left, right *int) bool { return *left > *right })
+
+	for i := range values {
+		list.Push(&values[i])
+	}
+
+	if *list.Peek() != 1 || list.Count() != 6 {
+		t.Errorf("Push => peek(%d)", *list.Peek())
+	}
+
+	values[4] = 0
+	list.Update(&values[4])
+	list.RemoveItem(&values[3])
+
+	items := list.ToArray()
+	if len(items) != 5 || *items[0] != 0 || *items[1] != 2 || *items[4] != 8 {
+		t.Errorf("ToArray => %d", len(items))
+	}
+
+	for _, expected := range []int{0, 2, 3, 5, 8} {
+		if item := list.Pop(); item == nil || *item != expected {
+			t.Errorf("Pop => expected %d", expected)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	if _, err := list.PopWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("PopWait(empty) => %v", err)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		list.Push(&values[0])
+	}()
+
+	if item, err := list.PopWait(context.Background()); err != nil || item != &values[0] {
+		t.Errorf("PopWait => %v, %v", item, err)
+	}
+}
+
+func TestDelayList(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	list := NewDelayList[string](clock)
+	respawn, buff, boss := "respawn", "buff", "boss"
+
+	list.Schedule(&respawn, start.Add(3*time.Second))
+	list.Schedule(&buff, start.Add(time.Second))
+	list.Schedule(&boss, start.Add(time.Minute))
+
+	if next, ok := list.NextDue(); !ok || !next.Equal(start.Add(time.Second)) {
+		t.Errorf("NextDue => %v, %v", next, ok)
+	}
+
+	list.Reschedule(&buff, start.Add(5*time.Second))
+	list.Cancel(&boss)
+
+	if due := list.PopDue(start.Add(4 * time.Second)); len(due) != 1 || due[0] != &respawn {
+		t.Errorf("PopDue => %v", due)
+	}
+
+	result := make(chan []*string)
+
+	go func() {
+		due, _ := list.WaitDue(context.Background())
+		result <- due
+	}()
+
+	//WaitDue must not return before the clock reaches the due time.
+	clock.Advance(4 * time.Second)
+
+	select {
+	case due := <-result:
+		t.Fatalf("WaitDue => returned early %v", due)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Second)
+
+	if due := <-result; len(due) != 1 || due[0] != &buff || list.Count() != 0 {
+		t.Errorf("WaitDue => %v", due)
+	}
+
+	//A cancelled WaitDue stops its timer, the clock keeps no waiter.
+	list.Schedule(&boss, clock.Now().Add(time.Hour))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	if _, err := list.WaitDue(ctx); err != context.Canceled {
+		t.Errorf("WaitDue(cancelled) => %v", err)
+	}
+
+	clock.locker.Lock()
+	waiters := len(clock.waiters)
+	clock.locker.Unlock()
+
+	if waiters != 0 {
+		t.Errorf("WaitDue(cancelled) => %d waiters left", waiters)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	list := NewPointerList[string](WithClock[string](clock))
+
+	a, b, c := "a", "b", "c"
+	expired := make([]*string, 0)
+
+	list.OnExpire(func(current *string) {
+		expired = append(expired, current)
+	})
+
+	list.Add(&a)
+	list.AddWithTTL(&b, time.Second)
+	list.AddWithTTL(&c, 2*time.Second)
+
+	if next := list.GetNext(); next != &a {
+		t.Fatalf("GetNext => %v", next)
+	}
+
+	clock.Advance(time.Second)
+
+	if list.Count() != 2 || len(expired) != 1 || expired[0] != &b {
+		t.Errorf("Count => %d, expired %v", list.Count(), expired)
+	}
+
+	//b expired, c is next.
+	if next := list.GetNext(); next != &c {
+		t.Errorf("GetNext => %v", next)
+	}
+
+	if list.Touch(&a) || !list.Touch(&c) {
+		t.Error("Touch => wrong result")
+	}
+
+	clock.Advance(time.Second)
+
+	if list.Count() != 2 {
+		t.Errorf("Touch => Count %d", list.Count())
+	}
+
+	clock.Advance(time.Second)
+
+	if list.Count() != 1 || len(expired) != 2 || expired[1] != &c {
+		t.Errorf("Count => %d, expired %v", list.Count(), expired)
+	}
+
+	tagList := NewGuardedTagList[string](WithClock[string](clock))
+	expiredKeys := make(chan string, 2)
+
+	tagList.OnExpire(func(key string, current *string) {
+		expiredKeys <- key
+	})
+
+	tagList.Add("player", &a)
+	tagList.AddWithTTL("player", &b, time.Second)
+	tagList.AddWithTTL("buff", &c, time.Second)
+
+	clock.Advance(time.Second)
+
+	if count := tagList.MapCount(); count["player"] != 1 || count["buff"] != 0 || tagList.TotalCount() != 1 {
+		t.Errorf("MapCount => %v", count)
+	}
+
+	if len(expiredKeys) != 2 {
+		t.Errorf("OnExpire => %d calls", len(expiredKeys))
+	}
+
+	//The janitor removes expired elements without another access.
+	guarded := NewGuardedPointerList[string](WithClock[string](clock))
+	done := make(chan *string, 1)
+
+	guarded.OnExpire(func(current *string) {
+		done <- current
+	})
+	guarded.AddWithTTL(&a, time.Second)
+	guarded.StartJanitor(time.Second)
+	defer guarded.Close()
+
+	for {
+		clock.Advance(time.Second)
+
+		select {
+		case current := <-done:
+			if current != &a {
+				t.Errorf("janitor => %v", current)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestExpireReentrant(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	a, b := "a", "b"
+
+	lists := map[string]GuardedTagList[string]{
+		"guarded": NewGuardedTagList[string](WithClock[string](clock)),
+		"sharded": NewShardedTagList[string](2, WithClock[string](clock)),
+	}
+
+	for name, tagList := range lists {
+		tagList := tagList
+		counts := make(chan int, 1)
+
+		//The callback uses the tag list, which deadlocks if it is still locked.
+		tagList.OnExpire(func(key string, current *string) {
+			counts <- tagList.TotalCount()
+		})
+
+		tagList.Add("player", &a)
+		tagList.AddWithTTL("player", &b, time.Second)
+		clock.Advance(time.Second)
+
+		done := make(chan bool)
+		go func() {
+			done <- tagList.Contains("player", &b)
+		}()
+
+		select {
+		case contains := <-done:
+			if contains || <-counts != 1 {
+				t.Errorf("%s: OnExpire => wrong result", name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: OnExpire => deadlock", name)
+		}
+	}
+
+	cache := NewLRUTagList[string](10)
+	counts := make(chan int, 1)
+
+	cache.OnExpire(func(key string, current *string) {
+		counts <- cache.TotalCount()
+	})
+
+	cache.Add("player", &a)
+	cache.AddWithTTL("player", &b, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	done := make(chan int)
+	go func() {
+		done <- cache.TotalCount()
+	}()
+
+	select {
+	case count := <-done:
+		if count != 1 || <-counts != 1 {
+			t.Errorf("cache: OnExpire => %d", count)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cache: OnExpire => deadlock")
+	}
+}
+
+func TestCache(t *testing.T) {
+	a, b, c, d := "a", "b", "c", "d"
+
+	lru := NewLRUTagList[string](3)
+	lru.Add("player", &a)
+	lru.Add("player", &b)
+	lru.Add("boss", &c)
+
+	if !lru.Contains("player", &a) {
+		t.Fatal("Contains => false")
+	}
+
+	//b is the least recently used.
+	lru.Add("boss", &d)
+
+	if lru.Contains("player", &b) || lru.TotalCount() != 3 {
+		t.Errorf("LRU => %v", lru.MapCount())
+	}
+
+	if stats := lru.Stats(); stats != (CacheStats{Hits: 1, Misses: 1, Evictions: 1}) {
+		t.Errorf("Stats => %+v", stats)
+	}
+
+	//A cleared tag is not counted.
+	lru.ClearList("player")
+	lru.Add("buff", &a)
+	lru.Add("buff", &b)
+
+	if lru.TotalCount() != 3 || lru.Contains("boss", &c) {
+		t.Errorf("ClearList => %v", lru.MapCount())
+	}
+
+	lfu := NewLFUTagList[string](2)
+	lfu.Add("player", &a)
+	lfu.Add("player", &b)
+	lfu.Contains("player", &a)
+	lfu.Contains("player", &a)
+
+	//b is used the least, the added element is not evicted.
+	lfu.Add("player", &c)
+	lfu.Add("player", &d)
+
+	if items := lfu.Get("player").ToArray(); len(items) != 2 || items[0] != &a || items[1] != &d {
+		t.Errorf("LFU => %v", items)
+	}
+
+	perKey := NewLRUTagList[string](0, WithPerKeyLimit(2))
+	perKey.Add("player", &a)
+	perKey.Add("player", &b)
+	perKey.Add("player", &c)
+	perKey.Add("boss", &d)
+
+	if count := perKey.MapCount(); count["player"] != 2 || count["boss"] != 1 || perKey.Contains("player", &a) {
+		t.Errorf("WithPerKeyLimit => %v", count)
+	}
+
+	perKey.Remove("player", &b)
+	perKey.Add("player", &a)
+
+	//The removed element is not tracked anymore, c is evicted next.
+	perKey.Add("player", &b)
+
+	if items := perKey.Get("player").ToArray(); len(items) != 2 || items[0] != &a || items[1] != &b {
+		t.Errorf("Remove => %v", items)
+	}
+
+	expiring := NewLRUTagList[int](100)
+	expired := 0
+	expiring.OnExpire(func(key string, current *int) { expired++ })
+
+	for i := 0; i < 1000; i++ {
+		expiring.AddWithTTL(fmt.Sprint(i%10), new(int), time.Nanosecond)
+	}
+
+	time.Sleep(time.Millisecond)
+	expiring.TotalCount()
+	expiring.Stats()
+
+	if cache := expiring.(*cacheTagList[int]); len(cache.entries) != 0 || cache.queue.Count() != 0 || expired != 1000 {
+		t.Errorf("AddWithTTL => %d entries, %d expired", len(cache.entries), expired)
+	}
+}
+
+func TestKeyLimit(t *testing.T) {
+	a, b, c, d := "a", "b", "c", "d"
+
+	list := NewTagList[string]()
+	list.SetKeyLimit("chat", 2, LimitDropOldest)
+	list.SetKeyLimit("boss", 1, LimitReject)
+
+	list.Add("chat", &a)
+	list.Add("chat", &b)
+
+	if evicted, err := list.Add("chat", &c); evicted != &a || err != nil {
+		t.Errorf("LimitDropOldest => %v, %v", evicted, err)
+	}
+
+	list.Add("boss", &a)
+
+	if _, err := list.Add("boss", &b); err == nil || list.Get("boss").Count() != 1 {
+		t.Errorf("LimitReject => %v", err)
+	}
+
+	guarded := NewGuardedTagList[string]()
+	guarded.SetDefaultLimit(2, LimitDropNewest)
+
+	guarded.Add("player", &a)
+	guarded.Add("player", &b)
+	guarded.Insert(0, "player", &c)
+
+	//a is removed, the index moves with it.
+	if evicted, err := guarded.Insert(2, "player", &d); evicted != &a || err != nil {
+		t.Errorf("LimitDropNewest => %v, %v", evicted, err)
+	}
+
+	if items := guarded.Get("player").ToArray(); len(items) != 2 || items[0] != &c || items[1] != &d {
+		t.Errorf("Insert => %v", items)
+	}
+
+	if _, err := guarded.Insert(5, "player", &a); err == nil {
+		t.Error("Insert => out of range")
+	}
+
+	if count := guarded.MapCountLimit()["player"]; count != (KeyCount{Count: 2, Limit: 2, Policy: LimitDropNewest}) {
+		t.Errorf("MapCountLimit => %+v", count)
+	}
+
+	guarded.SetKeyLimit("boss", 1, LimitReject)
+	guarded.Add("boss", &a)
+
+	if ok, err := guarded.TryAdd("boss", &b, time.Second); ok || err == nil {
+		t.Errorf("TryAdd LimitReject => %v, %v", ok, err)
+	}
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	//Holds the lock of the tag until release is closed.
+	go func() {
+		guarded.Get("player").Foreach(func(index int, current *string) bool {
+			close(locked)
+			<-release
+			return false
+		})
+		close(done)
+	}()
+
+	<-locked
+
+	if ok, err := guarded.TryAdd("player", &a, time.Millisecond); ok || err != nil {
+		t.Errorf("TryAdd locked => %v, %v", ok, err)
+	}
+
+	close(release)
+	<-done
+
+	//The timed out add evicted nothing.
+	if items := guarded.Get("player").ToArray(); len(items) != 2 || items[0] != &c || items[1] != &d {
+		t.Errorf("TryAdd locked => %v", items)
+	}
+
+	if ok, err := guarded.TryAdd("player", &a, time.Second); !ok || err != nil || guarded.Get("player").Get(1) != &a {
+		t.Errorf("TryAdd => %v, %v", ok, err)
+	}
+}
+
+func TestKeyLimitIndex(t *testing.T) {
+	byID := func(current *indexPlayer) any { return current.ID }
+
+	tags := NewTagList[indexPlayer]()
+	tags.SetKeyLimit("team", 2, LimitDropOldest)
+	tags.Add("team", &indexPlayer{ID: 1})
+	tags.Add("team", &indexPlayer{ID: 2})
+	tags.Get("team").CreateIndex("id", byID, Unique())
+
+	guarded := NewGuardedTagList[indexPlayer]()
+	guarded.SetKeyLimit("team", 2, LimitDropNewest)
+	guarded.Add("team", &indexPlayer{ID: 1})
+	guarded.Add("team", &indexPlayer{ID: 2})
+	guarded.Get("team").CreateIndex("id", byID, Unique())
+
+	//A rejected element evicts nothing.
+	if evicted, err := tags.Add("team", &indexPlayer{ID: 2}); evicted != nil || err == nil || tags.Get("team").Count() != 2 {
+		t.Errorf("TagList Add => %v, %v", evicted, err)
+	}
+
+	if evicted, err := tags.Insert(0, "team", &indexPlayer{ID: 1}); evicted != nil || err == nil || tags.Get("team").Count() != 2 {
+		t.Errorf("TagList Insert => %v, %v", evicted, err)
+	}
+
+	if evicted, err := guarded.Add("team", &indexPlayer{ID: 2}); evicted != nil || err == nil || guarded.Get("team").Count() != 2 {
+		t.Errorf("GuardedTagList Add => %v, %v", evicted, err)
+	}
+
+	if evicted, err := guarded.Insert(0, "team", &indexPlayer{ID: 1}); evicted != nil || err == nil || guarded.Get("team").Count() != 2 {
+		t.Errorf("GuardedTagList Insert => %v, %v", evicted, err)
+	}
+
+	if evicted, err := tags.Insert(1, "team", &indexPlayer{ID: 3}); evicted == nil || evicted.ID != 1 || err != nil || tags.Get("team").Get(0).ID != 3 {
+		t.Errorf("TagList Insert => %v, %v", evicted, err)
+	}
+
+	if evicted, err := guarded.Insert(0, "team", &indexPlayer{ID: 3}); evicted == nil || evicted.ID != 2 || err != nil || guarded.Get("team").Get(0).ID != 3 {
+		t.Errorf("GuardedTagList Insert => %v, %v", evicted, err)
+	}
+}
+
+func TestCOWPointerList(t *testing.T) {
+	list := NewCOWPointerList[int]()
+
+	for i := 0; i < 10; i++ {
+		value := i
+		list.Add(&value)
+	}
+
+	//A published array does not change.
+	snapshot := list.ToArray()
+	list.RemoveAt(0)
+	list.Sort(func(left *int, right *int) bool { return *left < *right })
+
+	if len(snapshot) != 10 || *snapshot[0] != 0 || list.Count() != 9 || *list.Get(0) != 9 {
+		t.Errorf("snapshot => %d, list %d", len(snapshot), list.Count())
+	}
+
+	if !list.RemoveAtNoSafe(0) || list.Count() != 8 || *list.Get(0) != 8 {
+		t.Errorf("RemoveAtNoSafe => %d", list.Count())
+	}
+
+	//Reads keep the published array.
+	published := list.ToArray()
+	list.Contains(published[0])
+	list.Lookup("none", 0)
+	MinBy[int](list, func(left *int, right *int) bool { return *left < *right })
+
+	if &list.ToArray()[0] != &published[0] {
+		t.Error("read => array copied")
+	}
+
+	list.Add(snapshot[0])
+
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for n := 0; n < 100; n++ {
+				value := n
+				list.Add(&value)
+				list.RemoveAt(0)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for n := 0; n < 100; n++ {
+				list.Foreach(func(index int, current *int) bool { return current != nil })
+				list.Contains(snapshot[0])
+
+				if list.Find(func(index int, current *int) bool { return current == nil }) != nil {
+					t.Error("Find => nil element")
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if list.Count() != 9 {
+		t.Errorf("Count => %d", list.Count())
+	}
+
+	clock := NewManualClock(time.Unix(0, 0))
+	expiring := NewCOWPointerList[int](WithClock[int](clock))
+	a, b := 1, 2
+
+	expiring.AddWithTTL(&a, time.Second)
+	clock.Advance(time.Second)
+
+	//Readers see the element until a writer purges it.
+	if expiring.Count() != 1 {
+		t.Errorf("Count => %d", expiring.Count())
+	}
+
+	expiring.Add(&b)
+
+	if expiring.Count() != 1 || expiring.Get(0) != &b {
+		t.Errorf("purge => %v", expiring.ToArray())
+	}
+}
+
+func benchmarkReadWrite(b *testing.B, list GuardedPointerList[int], writePercent int) {
+	for i := 0; i < 100; i++ {
+		value := i
+		list.Add(&value)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		value := 0
+
+		for i := 0; pb.Next(); i++ {
+			if i%100 < writePercent {
+				list.Add(&value)
+				list.RemoveAt(0)
+			} else {
+				list.Get(i % 100)
+			}
+		}
+	})
+}
+
+func BenchmarkReadWrite(b *testing.B) {
+	for _, writePercent := range []int{0, 1, 10, 50} {
+		b.Run(fmt.Sprintf("Guarded/%d%%write", writePercent), func(b *testing.B) {
+			benchmarkReadWrite(b, NewGuardedPointerList[int](), writePercent)
+		})
+
+		b.Run(fmt.Sprintf("COW/%d%%write", writePercent), func(b *testing.B) {
+			benchmarkReadWrite(b, NewCOWPointerList[int](), writePercent)
+		})
+	}
+}
+
+func TestShardedTagList(t *testing.T) {
+	list := NewShardedTagList[int](4)
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(key string) {
+			defer wg.Done()
+
+			for n := 0; n < 100; n++ {
+				value := n
+				list.Add(key, &value)
+			}
+		}(fmt.Sprintf("key%d", i))
+	}
+
+	wg.Wait()
+
+	if list.Count() != 8 || list.TotalCount() != 800 || list.MapCount()["key3"] != 100 {
+		t.Errorf("Count => %d, TotalCount %d", list.Count(), list.TotalCount())
+	}
+
+	if item := list.Find(func(index int, current *int) bool { return *current == 99 }); item == nil || *item != 99 {
+		t.Errorf("Find => %v", item)
+	}
+
+	visited := 0
+	list.Foreach(func(key string, index int, current *int, removeCurItem func()) bool {
+		if *current%2 == 0 {
+			removeCurItem()
+		}
+
+		visited++
+		return true
+	})
+
+	if visited != 800 || list.TotalCount() != 400 {
+		t.Errorf("Foreach => visited %d, TotalCount %d", visited, list.TotalCount())
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewShardedTagList[int](2)
+
+	if err := json.Unmarshal(data, restored); err != nil || restored.MapCount()["key5"] != 50 {
+		t.Errorf("json => %v, %v", restored.MapCount(), err)
+	}
+
+	list.Clear()
+
+	if list.Count() != 0 || list.Get("key1") != nil {
+		t.Errorf("Clear => %d", list.Count())
+	}
+}
+
+func benchmarkTagContention(b *testing.B, list GuardedTagList[int]) {
+	keys := make([]string, 64)
+
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+
+	var next int64
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		//Every goroutine uses its own keys.
+		key := keys[atomic.AddInt64(&next, 1)%int64(len(keys))]
+		value := 0
+
+		for pb.Next() {
+			list.Add(key, &value)
+			list.Remove(key, &value)
+		}
+	})
+}
+
+func BenchmarkTagContention(b *testing.B) {
+	b.Run("Guarded", func(b *testing.B) {
+		benchmarkTagContention(b, NewGuardedTagList[int]())
+	})
+
+	for _, shards := range []int{4, 16, 64} {
+		b.Run(fmt.Sprintf("Sharded/%d", shards), func(b *testing.B) {
+			benchmarkTagContention(b, NewShardedTagList[int](shards))
+		})
+	}
+}
+
+func TestImmutableList(t *testing.T) {
+	items := make([]*int, 2000)
+
+	for i := range items {
+		value := i
+		items[i] = &value
+	}
+
+	empty := NewImmutableList[int]()
+	list := empty
+
+	for _, item := range items {
+		list = list.Add(item)
+	}
+
+	if empty.Count() != 0 || list.Count() != 2000 || list.Get(1500) != items[1500] || list.Get(2000) != nil {
+		t.Fatalf("Add => %d", list.Count())
+	}
+
+	//Every change leaves the old version as it was.
+	changed, _ := list.Set(100, items[0])
+	changed, _ = changed.Insert(items[1], 40)
+	changed, _ = changed.RemoveAt(1999)
+	changed, _ = changed.Remove(items[5])
+
+	expected := append([]*int{}, items[:40]...)
+	expected = append(expected, items[1])
+	expected = append(expected, items[40:1998]...)
+	expected = append(expected, items[1999])
+	expected = append(expected[:5], expected[6:]...)
+	expected[100] = items[0]
+
+	if result := changed.ToArray(); len(result) != len(expected) {
+		t.Fatalf("changes => %d", len(result))
+	} else {
+		for i := range result {
+			if result[i] != expected[i] {
+				t.Fatalf("changes => index %d", i)
+			}
+		}
+	}
+
+	for i, item := range list.ToArray() {
+		if item != items[i] {
+			t.Fatalf("old version => index %d", i)
+		}
+	}
+
+	if _, err := list.Insert(items[0], 2001); err == nil {
+		t.Error("Insert => out of range")
+	}
+
+	builder := list.Transient()
+	builder.AddRange(items)
+	builder.Set(10, items[0])
+	built := builder.Persistent()
+
+	//The builder goes on without changing the built list.
+	builder.Set(11, items[0])
+	builder.Set(3999, items[0])
+
+	if built.Count() != 4000 || built.Get(10) != items[0] || built.Get(11) != items[11] || built.Get(3999) != items[1999] || list.Get(10) != items[10] {
+		t.Errorf("Transient => %d", built.Count())
+	}
+
+	pointerList := built.ToPointerList()
+	pointerList.RemoveAt(0)
+
+	if from := NewImmutableListFrom(pointerList); from.Count() != 3999 || from.Get(0) != items[1] {
+		t.Errorf("NewImmutableListFrom => %d", from.Count())
+	}
+}
+
+func TestHistory(t *testing.T) {
+	a, b, c, d := 1, 2, 3, 4
+	values := func(list PointerList[int]) []int {
+		result := make([]int, 0)
+		list.Foreach(func(index int, item *int) bool {
+			result = append(result, *item)
+			return true
+		})
+		return result
+	}
+
+	list := NewPointerList(WithHistory[int](0))
+	list.Add(&a)
+	list.Add(&b)
+	list.Add(&c)
+	list.Checkpoint("start")
+
+	list.Insert(&d, 1)
+	list.Remove(&a)
+	list.Reverse()
+	list.Sort(func(left *int, right *int) bool { return *left < *right })
+
+	if result := values(list); fmt.Sprint(result) != "[4 3 2]" {
+		t.Fatalf("changes => %v", result)
+	}
+
+	list.Undo()
+	list.Undo()
+
+	if result := values(list); fmt.Sprint(result) != "[4 2 3]" {
+		t.Errorf("Undo => %v", result)
+	}
+
+	list.Redo()
+
+	if result := values(list); fmt.Sprint(result) != "[3 2 4]" || !list.CanRedo() {
+		t.Errorf("Redo => %v", result)
+	}
+
+	list.Batch(func() {
+		list.Clear()
+		list.Add(&d)
+		list.Add(&a)
+	})
+
+	if result := values(list); fmt.Sprint(result) != "[4 1]" || list.CanRedo() {
+		t.Errorf("Batch => %v", result)
+	}
+
+	list.Undo()
+
+	if result := values(list); fmt.Sprint(result) != "[3 2 4]" {
+		t.Errorf("Undo Batch => %v", result)
+	}
+
+	if err := list.RevertTo("start"); err != nil || fmt.Sprint(values(list)) != "[1 2 3]" {
+		t.Errorf("RevertTo => %v, %v", values(list), err)
+	}
+
+	list.Add(&d)
+
+	//The steps after start were dropped by the Add.
+	list.Checkpoint("end")
+	list.Undo()
+
+	if err := list.RevertTo("missing"); err == nil {
+		t.Errorf("RevertTo => %v", err)
+	}
+
+	if err := list.RevertTo("end"); err != nil || fmt.Sprint(values(list)) != "[1 2 3 4]" {
+		t.Errorf("RevertTo redo => %v, %v", values(list), err)
+	}
+
+	short := NewGuardedPointerList(WithHistory[int](2))
+	short.Add(&a)
+	short.Add(&b)
+	short.Add(&c)
+
+	if !short.Undo() || !short.Undo() || short.Undo() || short.Count() != 1 {
+		t.Errorf("maxSteps => %d", short.Count())
+	}
+
+	if NewPointerList[int]().Undo() {
+		t.Error("Undo => no history")
+	}
+
+	tags := NewGuardedTagList(WithHistory[int](0))
+	tags.Add("level", &a)
+	tags.Batch("level", func() {
+		tags.Add("level", &b)
+		tags.Insert(0, "level", &c)
+	})
+
+	if !tags.Undo("level") || fmt.Sprint(values(tags.Get("level"))) != "[1]" || !tags.CanUndo("level") {
+		t.Errorf("TagList Undo => %v", values(tags.Get("level")))
+	}
+
+	if tags.Undo("missing") || tags.RevertTo("missing", "start") == nil {
+		t.Error("TagList Undo => missing tag")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	a, b, c := 1, 2, 3
+
+	list := NewGuardedPointerList[int]()
+	list.Add(&a)
+
+	version := list.Version()
+
+	if err := list.AddIfVersion(version, &b); err != nil || list.Version() == version {
+		t.Fatalf("AddIfVersion => %v", err)
+	}
+
+	//The version read before the Add is stale.
+	if err := list.AddIfVersion(version, &c); err != ErrConflict || GetErrorType(err) != Conflict {
+		t.Errorf("AddIfVersion => %v", err)
+	}
+
+	if err := list.SetIfVersion(list.Version(), 1, &c); err != nil || list.Get(1) != &c {
+		t.Errorf("SetIfVersion => %v", err)
+	}
+
+	if err := list.SetIfVersion(list.Version(), 5, &c); err == nil || err == ErrConflict {
+		t.Errorf("SetIfVersion => %v", err)
+	}
+
+	items := []*int{&c, &b, &a}
+
+	if err := list.ReplaceIfVersion(list.Version(), items); err != nil || list.Count() != 3 || list.Get(0) != &c {
+		t.Errorf("ReplaceIfVersion => %v", err)
+	}
+
+	//The items are copied.
+	items[0] = &a
+
+	if list.Get(0) != &c {
+		t.Error("ReplaceIfVersion => items are shared")
+	}
+
+	cow := NewCOWPointerList[int]()
+	cow.Add(&a)
+
+	if err := cow.AddIfVersion(cow.Version(), &b); err != nil || cow.Version() != 2 {
+		t.Errorf("COW Version => %d, %v", cow.Version(), err)
+	}
+
+	deque := NewDeque[int]()
+	deque.PushBack(&a)
+	deque.PopFront()
+
+	if deque.Version() != 2 {
+		t.Errorf("Deque Version => %d", deque.Version())
+	}
+
+	tags := NewGuardedTagList[int]()
+	tags.Add("red", &a)
+	tags.Add("blue", &b)
+
+	red := tags.Version("red")
+
+	if err := tags.AddIfVersion("blue", tags.Version("blue"), &c); err != nil || tags.Version("red") != red {
+		t.Errorf("Version(key) => %v", err)
+	}
+
+	//The version of a cleared tag does not start over.
+	tags.ClearList("red")
+
+	if tags.Version("red") <= red || tags.AddIfVersion("red", red, &c) != ErrConflict {
+		t.Errorf("ClearList => %d", tags.Version("red"))
+	}
+
+	if err := tags.AddIfVersion("red", tags.Version("red"), &c); err != nil || tags.Get("red").Count() != 1 {
+		t.Errorf("AddIfVersion => %v", err)
+	}
+
+	tags.SetKeyLimit("red", 1, LimitDropOldest)
+
+	if err := tags.AddIfVersion("red", tags.Version("red"), &a); GetErrorType(err) == Conflict || err == nil {
+		t.Errorf("AddIfVersion => %v", err)
+	}
+
+	if NewTagList[int]().Version("missing") != 0 {
+		t.Error("Version => missing tag")
+	}
+}
@@ -0,0 +1,307 @@
+package PointerList
+
+import (
+	"context"
+	"sort"
+)
+
+/////////////////////////////////
+//        Priority List        //
+/////////////////////////////////
+
+//Binary heap ordered by a SortPointerFunc[T]. Pop returns the element Sort(f) would place first.
+//Every pointer is held at most once.
+type PriorityList[T any] interface {
+	BASE
+
+	//Adds an object. An object that is already in the list is moved to its new position, same as Update.
+	Push(item *T)
+	//Removes and returns the first element, or nil.
+	Pop() *T
+	//Returns the first element without removing it, or nil.
+	Peek() *T
+	//Moves the element after its priority changed. Returns false if it is not in the list.
+	Update(item *T) bool
+	//Removes the element. Returns false if it is not in the list.
+	RemoveItem(item *T) bool
+	//Determines whether an element is in the PriorityList[T].
+	Contains(item *T) bool
+	//Returns the number of elements in a sequence.
+	Count() int
+	//Removes all elements from the PriorityList[T].
+	Clear()
+	//Copies the elements in the order Pop would return them.
+	ToArray() []*T
+}
+
+//PriorityList[T] protected by mutex.
+type GuardedPriorityList[T any] interface {
+	PriorityList[T]
+
+	//Removes and returns the first element, waiting for a Push while the list is empty. Returns ctx.Err() if the context is done.
+	PopWait(ctx context.Context) (*T, error)
+}
+
+type priorityList[T any] struct {
+	BASE
+	heap    []*T
+	indexOf map[*T]int
+	f       SortPointerFunc[T]
+	//Closed and replaced by Push, only set for the guarded list.
+	pushed chan struct{}
+}
+
+func NewPriorityList[T any](f SortPointerFunc[T]) PriorityList[T] {
+	return &priorityList[T]{
+		heap:    make([]*T, 0),
+		indexOf: make(map[*T]int),
+		f:       f,
+	}
+}
+
+func NewGuardedPriorityList[T any](f SortPointerFunc[T]) GuardedPriorityList[T] {
+	baseList := &lockerBase{}
+	return &priorityList[T]{
+		heap:    make([]*T, 0),
+		indexOf: make(map[*T]int),
+		f:       f,
+		BASE:    baseList,
+		pushed:  make(chan struct{}),
+	}
+}
+
+//Adds an object. An object that is already in the list is moved to its new position, same as Update.
+func (l *priorityList[T]) Push(item *T) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if index, ok := l.indexOf[item]; ok {
+		l.fix(index)
+		return
+	}
+
+	l.heap = append(l.heap, item)
+	l.indexOf[item] = len(l.heap) - 1
+	l.up(len(l.heap) - 1)
+
+	if l.pushed != nil {
+		close(l.pushed)
+		l.pushed = make(chan struct{})
+	}
+}
+
+//Removes and returns the first element, or nil.
+func (l *priorityList[T]) Pop() *T {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.pop()
+}
+
+//Returns the first element without removing it, or nil.
+func (l *priorityList[T]) Peek() *T {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if len(l.heap) == 0 {
+		return nil
+	}
+
+	return l.heap[0]
+}
+
+//Moves the element after its priority changed. Returns false if it is not in the list.
+func (l *priorityList[T]) Update(item *T) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	index, ok := l.indexOf[item]
+	if ok {
+		l.fix(index)
+	}
+
+	return ok
+}
+
+//Removes the element. Returns false if it is not in the list.
+func (l *priorityList[T]) RemoveItem(item *T) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	index, ok := l.indexOf[item]
+	if ok {
+		l.removeAt(index)
+	}
+
+	return ok
+}
+
+//Determines whether an element is in the PriorityList[T].
+func (l *priorityList[T]) Contains(item *T) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	_, ok := l.indexOf[item]
+	return ok
+}
+
+//Returns the number of elements in a sequence.
+func (l *priorityList[T]) Count() int {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return len(l.heap)
+}
+
+//Removes all elements from the PriorityList[T].
+func (l *priorityList[T]) Clear() {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	l.heap = make([]*T, 0)
+	l.indexOf = make(map[*T]int)
+}
+
+//Copies the elements in the order Pop would return them.
+func (l *priorityList[T]) ToArray() []*T {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	items := make([]*T, len(l.heap))
+	copy(items, l.heap)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return l.f(items[j], items[i])
+	})
+
+	return items
+}
+
+//Removes and returns the first element, waiting for a Push while the list is empty. Returns ctx.Err() if the context is done.
+func (l *priorityList[T]) PopWait(ctx context.Context) (*T, error) {
+	for {
+		l.start()
+
+		if len(l.heap) > 0 {
+			item := l.pop()
+			l.end()
+
+			return item, nil
+		}
+
+		pushed := l.pushed
+		l.end()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-pushed:
+		}
+	}
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//Reports whether the element at i comes before the element at j.
+func (l *priorityList[T]) less(i int, j int) bool {
+	return l.f(l.heap[j], l.heap[i])
+}
+
+func (l *priorityList[T]) swap(i int, j int) {
+	l.heap[i], l.heap[j] = l.heap[j], l.heap[i]
+	l.indexOf[l.heap[i]] = i
+	l.indexOf[l.heap[j]] = j
+}
+
+func (l *priorityList[T]) up(index int) {
+	for index > 0 {
+		parent := (index - 1) / 2
+
+		if !l.less(index, parent) {
+			break
+		}
+
+		l.swap(index, parent)
+		index = parent
+	}
+}
+
+//Reports whether the element moved.
+func (l *priorityList[T]) down(index int) bool {
+	start := index
+
+	for {
+		child := 2*index + 1
+
+		if child >= len(l.heap) {
+			break
+		}
+
+		if right := child + 1; right < len(l.heap) && l.less(right, child) {
+			child = right
+		}
+
+		if !l.less(child, index) {
+			break
+		}
+
+		l.swap(index, child)
+		index = child
+	}
+
+	return index > start
+}
+
+func (l *priorityList[T]) fix(index int) {
+	if !l.down(index) {
+		l.up(index)
+	}
+}
+
+func (l *priorityList[T]) pop() *T {
+	if len(l.heap) == 0 {
+		return nil
+	}
+
+	item := l.heap[0]
+	l.removeAt(0)
+
+	return item
+}
+
+func (l *priorityList[T]) removeAt(index int) {
+	last := len(l.heap) - 1
+	item := l.heap[index]
+
+	if index != last {
+		l.swap(index, last)
+	}
+
+	l.heap[last] = nil
+	l.heap = l.heap[:last]
+	delete(l.indexOf, item)
+
+	if index != last {
+		l.fix(index)
+	}
+}
@@ -0,0 +1,263 @@
+package PointerList
+
+import "sort"
+
+/////////////////////////////////
+//            Query            //
+/////////////////////////////////
+
+//Lazy query over a PointerList[T] or a TagList[T]. Nothing is evaluated until a terminal method (ToList, ToArray, First, Count, Any) is called.
+type Queryable[T any] interface {
+	//Filters the elements based on a predicate.
+	Where(f PredicatePointerFunc[T]) Queryable[T]
+	//Sorts the elements using the specified LessPointerFunc[T]. The sort is stable.
+	OrderBy(less LessPointerFunc[T]) Queryable[T]
+	//Performs a subsequent ordering of the elements that are equal for the previous OrderBy/ThenBy.
+	ThenBy(less LessPointerFunc[T]) Queryable[T]
+	//Bypasses the specified number of elements and then returns the remaining elements.
+	Skip(count int) Queryable[T]
+	//Returns the specified number of elements from the start.
+	Take(count int) Queryable[T]
+
+	//Runs the query and returns the result as a new PointerList[T].
+	ToList() PointerList[T]
+	//Runs the query and returns the result as a slice.
+	ToArray() []*T
+	//Runs the query until the first element. Returns nil if there is no element.
+	First() *T
+	//Runs the query and returns the number of elements.
+	Count() int
+	//Runs the query until the first element and reports whether there is one.
+	Any() bool
+
+	//Calls yield for every element of the query until yield returns false.
+	iterate(yield func(current *T) bool)
+}
+
+//Element of a tag list query.
+type TagItem[T any] struct {
+	Key   string
+	Index int
+	Value *T
+}
+
+type query[T any] struct {
+	seq func(yield func(current *T) bool)
+
+	//Set only if the last step is OrderBy/ThenBy, so that ThenBy can extend the ordering.
+	unordered func(yield func(current *T) bool)
+	orders    []LessPointerFunc[T]
+}
+
+//Creates a lazy query over the PointerList[T]. The list is read when the query runs.
+func Query[T any](l PointerList[T]) Queryable[T] {
+	return &query[T]{
+		seq: func(yield func(current *T) bool) {
+			for _, item := range snapshotOf[T](l) {
+				if !yield(item) {
+					return
+				}
+			}
+		},
+	}
+}
+
+//Creates a lazy query over the elements of every tag, ordered by key. The tag list is read when the query runs.
+func QueryTags[T any](l TagSource[T]) Queryable[TagItem[T]] {
+	return &query[TagItem[T]]{
+		seq: func(yield func(current *TagItem[T]) bool) {
+			items := l.ToArrayMap()
+			keys := make([]string, 0, len(items))
+
+			for key := range items {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				for index, item := range items[key] {
+					if !yield(&TagItem[T]{Key: key, Index: index, Value: item}) {
+						return
+					}
+				}
+			}
+		},
+	}
+}
+
+//Projects each element of the query into a new form.
+func Select[T any, U any](q Queryable[T], f MapPointerFunc[T, U]) Queryable[U] {
+	return &query[U]{
+		seq: func(yield func(current *U) bool) {
+			q.iterate(func(current *T) bool {
+				return yield(f(current))
+			})
+		},
+	}
+}
+
+//Filters the elements based on a predicate.
+func (q *query[T]) Where(f PredicatePointerFunc[T]) Queryable[T] {
+	return &query[T]{
+		seq: func(yield func(current *T) bool) {
+			q.seq(func(current *T) bool {
+				if f(current) {
+					return yield(current)
+				}
+
+				return true
+			})
+		},
+	}
+}
+
+//Sorts the elements using the specified LessPointerFunc[T]. The sort is stable.
+func (q *query[T]) OrderBy(less LessPointerFunc[T]) Queryable[T] {
+	return newOrderedQuery(q.seq, []LessPointerFunc[T]{less})
+}
+
+//Performs a subsequent ordering of the elements that are equal for the previous OrderBy/ThenBy.
+func (q *query[T]) ThenBy(less LessPointerFunc[T]) Queryable[T] {
+	if q.orders == nil {
+		return q.OrderBy(less)
+	}
+
+	orders := make([]LessPointerFunc[T], len(q.orders), len(q.orders)+1)
+	copy(orders, q.orders)
+
+	return newOrderedQuery(q.unordered, append(orders, less))
+}
+
+//Bypasses the specified number of elements and then returns the remaining elements.
+func (q *query[T]) Skip(count int) Queryable[T] {
+	return &query[T]{
+		seq: func(yield func(current *T) bool) {
+			skipped := 0
+
+			q.seq(func(current *T) bool {
+				if skipped < count {
+					skipped++
+					return true
+				}
+
+				return yield(current)
+			})
+		},
+	}
+}
+
+//Returns the specified number of elements from the start.
+func (q *query[T]) Take(count int) Queryable[T] {
+	return &query[T]{
+		seq: func(yield func(current *T) bool) {
+			if count <= 0 {
+				return
+			}
+
+			taken := 0
+
+			q.seq(func(current *T) bool {
+				taken++
+				return yield(current) && taken < count
+			})
+		},
+	}
+}
+
+//Runs the query and returns the result as a new PointerList[T].
+func (q *query[T]) ToList() PointerList[T] {
+	return &pointerList[T]{
+		list: q.ToArray(),
+	}
+}
+
+//Runs the query and returns the result as a slice.
+func (q *query[T]) ToArray() []*T {
+	retList := make([]*T, 0)
+
+	q.seq(func(current *T) bool {
+		retList = append(retList, current)
+		return true
+	})
+
+	return retList
+}
+
+//Runs the query until the first element. Returns nil if there is no element.
+func (q *query[T]) First() *T {
+	var first *T
+
+	q.seq(func(current *T) bool {
+		first = current
+		return false
+	})
+
+	return first
+}
+
+//Runs the query and returns the number of elements.
+func (q *query[T]) Count() int {
+	count := 0
+
+	q.seq(func(current *T) bool {
+		count++
+		return true
+	})
+
+	return count
+}
+
+//Runs the query until the first element and reports whether there is one.
+func (q *query[T]) Any() bool {
+	found := false
+
+	q.seq(func(current *T) bool {
+		found = true
+		return false
+	})
+
+	return found
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func (q *query[T]) iterate(yield func(current *T) bool) {
+	q.seq(yield)
+}
+
+//Ordering has to see every element, so it collects the previous steps before yielding.
+func newOrderedQuery[T any](unordered func(yield func(current *T) bool), orders []LessPointerFunc[T]) *query[T] {
+	return &query[T]{
+		seq: func(yield func(current *T) bool) {
+			items := make([]*T, 0)
+
+			unordered(func(current *T) bool {
+				items = append(items, current)
+				return true
+			})
+
+			sort.SliceStable(items, func(i, j int) bool {
+				for _, less := range orders {
+					if less(items[i], items[j]) {
+						return true
+					} else if less(items[j], items[i]) {
+						return false
+					}
+				}
+
+				return false
+			})
+
+			for _, item := range items {
+				if !yield(item) {
+					return
+				}
+			}
+		},
+		unordered: unordered,
+		orders:    orders,
+	}
+}
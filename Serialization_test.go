@@ -0,0 +1,359 @@
+package PointerList
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type serialPlayer struct {
+	ID     int
+	Health float64
+}
+
+type serialResponse struct {
+	Players PointerList[serialPlayer]
+	Teams   GuardedTagList[serialPlayer]
+}
+
+func TestJSON(t *testing.T) {
+	response := serialResponse{
+		Players: NewPointerList[serialPlayer](),
+		Teams:   NewGuardedTagList[serialPlayer](),
+	}
+
+	response.Players.Add(&serialPlayer{ID: 1, Health: 100})
+	response.Players.Add(nil)
+	response.Teams.Add("red", response.Players.Get(0))
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"Players":[{"ID":1,"Health":100},null],"Teams":{"red":[{"ID":1,"Health":100}]}}` {
+		t.Errorf("Marshal => %s", data)
+	}
+
+	decoded := serialResponse{
+		Players: NewGuardedPointerList[serialPlayer](),
+		Teams:   NewGuardedTagList[serialPlayer](),
+	}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Players.Count() != 2 || decoded.Players.Get(0).ID != 1 || decoded.Players.Get(1) != nil {
+		t.Errorf("Unmarshal => players(%d)", decoded.Players.Count())
+	}
+
+	if decoded.Teams.TotalCount() != 1 || decoded.Teams.Get("red").Get(0).Health != 100 {
+		t.Errorf("Unmarshal => teams(%v)", decoded.Teams.MapCount())
+	}
+}
+
+func TestJSONStream(t *testing.T) {
+	list := NewPointerList[serialPlayer]()
+	tags := NewTagList[serialPlayer]()
+
+	for i := 0; i < 100; i++ {
+		player := &serialPlayer{ID: i}
+		list.Add(player)
+		tags.Add([]string{"a", "b"}[i%2], player)
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := NewEncoder[serialPlayer](buf)
+
+	if err := encoder.Encode(list); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := encoder.EncodeTags(tags); err != nil {
+		t.Fatal(err)
+	}
+
+	decodedList := NewGuardedPointerList[serialPlayer]()
+	decodedTags := NewGuardedTagList[serialPlayer]()
+	decoder := NewDecoder[serialPlayer](buf)
+
+	if err := decoder.Decode(decodedList); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := decoder.DecodeTags(decodedTags); err != nil {
+		t.Fatal(err)
+	}
+
+	if decodedList.Count() != 100 || decodedList.Get(99).ID != 99 {
+		t.Errorf("Decode => count(%d)", decodedList.Count())
+	}
+
+	if counts := decodedTags.MapCount(); counts["a"] != 50 || counts["b"] != 50 {
+		t.Errorf("DecodeTags => %v", counts)
+	}
+}
+
+//Compact Codec: ID (uvarint) | Health (float64 bits)
+type serialPlayerCodec struct{}
+
+func (serialPlayerCodec) Marshal(item *serialPlayer) ([]byte, error) {
+	buf := make([]byte, binary.MaxVarintLen64+8)
+	n := binary.PutUvarint(buf, uint64(item.ID))
+	binary.BigEndian.PutUint64(buf[n:], math.Float64bits(item.Health))
+
+	return buf[:n+8], nil
+}
+
+func (serialPlayerCodec) Unmarshal(data []byte) (*serialPlayer, error) {
+	id, n := binary.Uvarint(data)
+	return &serialPlayer{ID: int(id), Health: math.Float64frombits(binary.BigEndian.Uint64(data[n:]))}, nil
+}
+
+func TestSnapshot(t *testing.T) {
+	tags := NewGuardedTagList[serialPlayer]()
+
+	for i := 0; i < 20; i++ {
+		tags.Add([]string{"red", "blue"}[i%2], &serialPlayer{ID: i, Health: float64(i) / 2})
+	}
+	tags.Add("red", nil)
+	tags.Add("empty", nil)
+	tags.RemoveAt("empty", 0)
+
+	buf := &bytes.Buffer{}
+
+	if _, err := tags.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewGuardedTagList[serialPlayer]()
+
+	if _, err := restored.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	if counts := restored.MapCount(); counts["red"] != 11 || counts["blue"] != 10 || counts["empty"] != 0 {
+		t.Errorf("ReadFrom => %v", counts)
+	}
+
+	//The tags are written in order, the same tag list writes the same bytes.
+	for i := 0; i < 10; i++ {
+		again := &bytes.Buffer{}
+
+		if _, err := restored.WriteTo(again); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(again.Bytes(), buf.Bytes()) {
+			t.Fatal("WriteTo => bytes differ")
+		}
+	}
+
+	if restored.Get("blue").Get(2).Health != 2.5 || restored.Get("red").Get(10) != nil {
+		t.Errorf("ReadFrom => blue[2](%v)", restored.Get("blue").Get(2))
+	}
+
+	list := NewPointerList[serialPlayer]()
+	list.AddRange(tags.Get("blue").ToArray())
+	buf.Reset()
+
+	if _, err := WriteSnapshot[serialPlayer](buf, list, serialPlayerCodec{}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	restoredList := NewGuardedPointerList[serialPlayer]()
+
+	if _, err := ReadSnapshot[serialPlayer](bytes.NewReader(data), restoredList, serialPlayerCodec{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if restoredList.Count() != 10 || restoredList.Get(9).ID != 19 || restoredList.Get(9).Health != 9.5 {
+		t.Errorf("ReadSnapshot => count(%d)", restoredList.Count())
+	}
+
+	corrupted := append([]byte{}, data...)
+	corrupted[10] ^= 0xFF
+
+	if _, err := ReadSnapshot[serialPlayer](bytes.NewReader(corrupted), restoredList, serialPlayerCodec{}); GetErrorType(err) != SnapshotChecksumMismatch {
+		t.Errorf("corrupted => err(%v)", err)
+	}
+
+	version := append([]byte{}, data...)
+	version[4] = 99
+
+	if _, err := ReadSnapshot[serialPlayer](bytes.NewReader(version), restoredList, serialPlayerCodec{}); GetErrorType(err) != UnsupportedSnapshotVersion {
+		t.Errorf("version => err(%v)", err)
+	}
+
+	if _, err := ReadSnapshot[serialPlayer](bytes.NewReader(data[:len(data)-3]), restoredList, serialPlayerCodec{}); GetErrorType(err) != InvalidSnapshot {
+		t.Errorf("truncated => err(%v)", err)
+	}
+
+	if _, err := restoredList.ReadFrom(bytes.NewReader(data)); err == nil || restoredList.Count() != 10 {
+		t.Errorf("gob into compact snapshot => err(%v) count(%d)", err, restoredList.Count())
+	}
+}
+
+func TestSnapshotIdentity(t *testing.T) {
+	shared := &serialPlayer{ID: 7, Health: 70}
+
+	tags := NewGuardedTagList[serialPlayer]()
+	tags.Add("red", shared)
+	tags.Add("alive", shared)
+	tags.Add("alive", &serialPlayer{ID: 8})
+
+	list := NewPointerList[serialPlayer]()
+	list.Add(shared)
+	list.Add(nil)
+
+	bundle := NewSnapshotBundle[serialPlayer](nil)
+	bundle.AddTags("teams", tags)
+	bundle.AddList("players", list)
+
+	buf := &bytes.Buffer{}
+
+	if _, err := bundle.WriteTo(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restoredTags := NewGuardedTagList[serialPlayer]()
+	restoredList := NewPointerList[serialPlayer]()
+
+	restored := NewSnapshotBundle[serialPlayer](nil)
+	restored.AddTags("teams", restoredTags)
+	restored.AddList("players", restoredList)
+
+	if _, err := restored.ReadFrom(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restoredShared := restoredTags.Get("red").Get(0)
+
+	if restoredShared == nil || restoredShared.ID != 7 || restoredTags.Get("alive").Get(0) != restoredShared || restoredList.Get(0) != restoredShared {
+		t.Errorf("ReadFrom => shared pointer is not shared")
+	}
+
+	if restoredList.Get(1) != nil || restoredTags.Get("alive").Get(1).ID != 8 {
+		t.Errorf("ReadFrom => players(%d)", restoredList.Count())
+	}
+
+	if !restoredTags.Remove("alive", restoredList.Get(0)) || restoredTags.Get("alive").Count() != 1 {
+		t.Errorf("Remove => restored pointer does not match")
+	}
+}
+
+type csvPlayer struct {
+	ID     int     `list:"id"`
+	Name   string  `list:"name"`
+	Health float64 `list:"hp"`
+	secret string
+	Cache  []int `list:"-"`
+}
+
+func TestCSV(t *testing.T) {
+	list := NewPointerList[csvPlayer]()
+	list.Add(&csvPlayer{ID: 1, Name: "Ann, the first", Health: 99.5, secret: "x"})
+	list.Add(&csvPlayer{ID: 2, Name: "Bob"})
+
+	buf := bytes.Buffer{}
+	if err := list.ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "id,name,hp\n1,\"Ann, the first\",99.5\n2,Bob,0\n" {
+		t.Errorf("ExportCSV => %q", buf.String())
+	}
+
+	decoded := NewPointerList[csvPlayer]()
+	if err := decoded.ImportCSV(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Count() != 2 || decoded.Get(0).Name != "Ann, the first" || decoded.Get(0).Health != 99.5 || decoded.Get(1).ID != 2 {
+		t.Errorf("ImportCSV => %d", decoded.Count())
+	}
+
+	buf.Reset()
+	err := list.ExportTSV(&buf, Column[csvPlayer]{Header: "player", Value: func(current *csvPlayer) string {
+		return strings.ToUpper(current.Name)
+	}})
+
+	if err != nil || buf.String() != "player\nANN, THE FIRST\nBOB\n" {
+		t.Errorf("ExportTSV => %q, %v", buf.String(), err)
+	}
+
+	rejected := NewPointerList[csvPlayer]()
+	err = rejected.ImportCSV(strings.NewReader("id\n1\nx\n"), nil)
+
+	if err == nil || !strings.Contains(err.Error(), "csv record 3") || rejected.Count() != 0 {
+		t.Errorf("ImportCSV(invalid) => %v, %d", err, rejected.Count())
+	}
+
+	tags := NewGuardedTagList[csvPlayer]()
+	tags.Add("red", list.Get(0))
+	tags.Add("blue", list.Get(1))
+
+	buf.Reset()
+	if err := tags.ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "tag,id,name,hp\nblue,2,Bob,0\nred,1,\"Ann, the first\",99.5\n" {
+		t.Errorf("ExportCSV(tags) => %q", buf.String())
+	}
+
+	decodedTags := NewTagList[csvPlayer]()
+	err = decodedTags.ImportCSV(&buf, func(record map[string]string) (*csvPlayer, error) {
+		id, err := strconv.Atoi(record["id"])
+		return &csvPlayer{ID: id, Name: record["name"]}, err
+	})
+
+	if err != nil || decodedTags.TotalCount() != 2 || decodedTags.Get("red").Get(0).ID != 1 || decodedTags.Get("blue").Get(0).Name != "Bob" {
+		t.Errorf("ImportCSV(tags) => %v, %v", err, decodedTags.MapCount())
+	}
+
+	limited := NewTagList[csvPlayer]()
+	limited.SetKeyLimit("red", 2, LimitReject)
+	limited.Add("red", &csvPlayer{ID: 9})
+
+	err = limited.ImportCSV(strings.NewReader("tag,id\nblue,1\nred,2\nred,3\n"), nil)
+
+	if err == nil || limited.TotalCount() != 1 {
+		t.Errorf("ImportCSV(limit) => %v, %v", err, limited.MapCount())
+	}
+}
+
+type labeledPlayer struct {
+	ID    int    `list:"id"`
+	Label string `list:"tag"`
+}
+
+func TestCSVTagField(t *testing.T) {
+	list := NewPointerList[labeledPlayer]()
+	list.Add(&labeledPlayer{ID: 1, Label: "mvp"})
+
+	buf := bytes.Buffer{}
+	list.ExportCSV(&buf)
+
+	decoded := NewPointerList[labeledPlayer]()
+	if err := decoded.ImportCSV(&buf, nil); err != nil || decoded.Count() != 1 || decoded.Get(0).Label != "mvp" {
+		t.Errorf("ImportCSV => %v, %d", err, decoded.Count())
+	}
+
+	tags := NewGuardedTagList[labeledPlayer]()
+	tags.Add("red", list.Get(0))
+
+	buf.Reset()
+	tags.ExportCSV(&buf)
+
+	decodedTags := NewGuardedTagList[labeledPlayer]()
+	if err := decodedTags.ImportCSV(&buf, nil); err != nil || decodedTags.Get("red").Get(0).Label != "mvp" {
+		t.Errorf("ImportCSV(tags) => %v, %v", err, decodedTags.MapCount())
+	}
+}
@@ -0,0 +1,204 @@
+package PointerList
+
+/////////////////////////////////
+//          Pointer Set        //
+/////////////////////////////////
+
+//PointerList[T] that holds every pointer at most once, in insertion order.
+//Add, AddRange and InsertRange skip or reject pointers that are already in the set.
+//Contains is O(1). IndexOf is O(1) until the set changes in front of the element, the first IndexOf after that renumbers
+//the elements from the change up to the element, O(n) at worst, the same order as the change shifting the array.
+type PointerSet[T any] interface {
+	PointerList[T]
+
+	//Returns the index of the element, or -1.
+	IndexOf(item *T) int
+	//Adds an object to the end of the PointerSet[T]. Returns false if it is already in the set,
+	//or false and the error of the Unique index that rejected it.
+	AddIfAbsent(item *T) (bool, error)
+	//Returns a new set with the elements of the set followed by the elements of other that are not in the set.
+	Union(other PointerList[T]) PointerSet[T]
+	//Returns a new set with the elements of the set that are also in other.
+	Intersect(other PointerList[T]) PointerSet[T]
+	//Returns a new set with the elements of the set that are not in other.
+	Except(other PointerList[T]) PointerSet[T]
+	//Determines whether every element of the set is in other.
+	IsSubsetOf(other PointerList[T]) bool
+}
+
+//PointerSet[T] protected by mutex.
+type GuardedPointerSet[T any] interface {
+	PointerSet[T]
+	GuardedPointerList[T]
+}
+
+func NewPointerSet[T any](options ...ListOption[T]) PointerSet[T] {
+	set := newPointerList(make([]*T, 0), nil, options)
+	set.positions = newSetPositions[T]()
+
+	return set
+}
+
+func NewGuardedPointerSet[T any](options ...ListOption[T]) GuardedPointerSet[T] {
+	baseList := &lockerBase{}
+	set := newPointerList(make([]*T, 0), baseList, options)
+	set.positions = newSetPositions[T]()
+
+	return set
+}
+
+//Returns the index of the element, or -1.
+func (l *pointerList[T]) IndexOf(item *T) int {
+	if l.BASE != nil {
+		l.startRead()
+		defer l.endRead()
+	}
+
+	return l.find(item)
+}
+
+//Adds an object to the end of the PointerSet[T]. Returns false if it is already in the set,
+//or false and the DuplicateKey error of the Unique index that rejected it.
+func (l *pointerList[T]) AddIfAbsent(item *T) (bool, error) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.positions != nil && l.has(item) {
+		return false, nil
+	} else if err := l.indexRejects(item); err != nil {
+		return false, err
+	}
+
+	l.list = append(l.list, item)
+	l.indexAdd(item, len(l.list)-1)
+
+	return true, nil
+}
+
+//Returns a new set with the elements of the set followed by the elements of other that are not in the set.
+//The lists are copied one after the other, so no lock is held across both.
+func (l *pointerList[T]) Union(other PointerList[T]) PointerSet[T] {
+	result := l.newSet()
+
+	result.AddRange(l.snapshot())
+	result.AddRange(snapshotOf[T](other))
+
+	return result
+}
+
+//Returns a new set with the elements of the set that are also in other.
+func (l *pointerList[T]) Intersect(other PointerList[T]) PointerSet[T] {
+	return l.filterBy(other, true)
+}
+
+//Returns a new set with the elements of the set that are not in other.
+func (l *pointerList[T]) Except(other PointerList[T]) PointerSet[T] {
+	return l.filterBy(other, false)
+}
+
+//Determines whether every element of the set is in other.
+func (l *pointerList[T]) IsSubsetOf(other PointerList[T]) bool {
+	items := l.snapshot()
+	contains := l.matcher(snapshotOf[T](other))
+
+	for _, item := range items {
+		if !contains(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func (l *pointerList[T]) filterBy(other PointerList[T], keep bool) PointerSet[T] {
+	items := l.snapshot()
+	contains := l.matcher(snapshotOf[T](other))
+	result := l.newSet()
+
+	for _, item := range items {
+		if contains(item) == keep {
+			result.Add(item)
+		}
+	}
+
+	return result
+}
+
+//Position of every element of a set. Positions from stale on are renumbered lazily, up to the element looked up,
+//so a removal does not have to update every following element and removing from the front renumbers one element.
+type setPositions[T any] struct {
+	positions map[*T]int
+	stale     int
+}
+
+func newSetPositions[T any]() *setPositions[T] {
+	return &setPositions[T]{
+		positions: make(map[*T]int),
+	}
+}
+
+func (s *setPositions[T]) contains(item *T) bool {
+	_, ok := s.positions[item]
+	return ok
+}
+
+func (s *setPositions[T]) indexOf(item *T, list []*T) int {
+	index, ok := s.positions[item]
+	if !ok {
+		return -1
+	}
+
+	//A stored position is right if the element is there, every element before stale is at its stored position.
+	if index < len(list) && list[index] == item {
+		return index
+	}
+
+	for i := s.stale; i < len(list); i++ {
+		s.positions[list[i]] = i
+
+		if list[i] == item {
+			s.stale = i + 1
+			return i
+		}
+	}
+
+	return -1
+}
+
+//The element was placed at index, length is the new length of the list.
+func (s *setPositions[T]) add(item *T, index int, length int) {
+	s.positions[item] = index
+
+	if index < length-1 {
+		s.moved(index + 1)
+	} else if s.stale == index {
+		s.stale = length
+	}
+}
+
+func (s *setPositions[T]) remove(item *T, index int) {
+	delete(s.positions, item)
+	s.moved(index)
+}
+
+//The positions from index on are no longer valid.
+func (s *setPositions[T]) moved(index int) {
+	if index < s.stale {
+		s.stale = index
+	}
+}
+
+func (s *setPositions[T]) rebuild(list []*T) {
+	s.positions = make(map[*T]int, len(list))
+
+	for i := 0; i < len(list); i++ {
+		s.positions[list[i]] = i
+	}
+	s.stale = len(list)
+}
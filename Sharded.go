@@ -0,0 +1,579 @@
+package PointerList
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+/////////////////////////////////
+//      Sharded Tag List       //
+/////////////////////////////////
+
+type shardedTagList[T any] struct {
+	shards  []*guardedTagList[T]
+	options []ListOption[T]
+	locker  sync.Mutex
+	stop    chan struct{}
+}
+
+//GuardedTagList[T] split into shards by the hash of the key, each with its own lock and map. An operation on one key
+//takes only the lock of its shard. TotalCount, Find, Foreach, Clear and the other operations over every key take the
+//locks of all shards in order, so they see every shard at the same point.
+//A shards < 1 is stored as 1. The options are applied to the list of every tag.
+func NewShardedTagList[T any](shards int, options ...ListOption[T]) GuardedTagList[T] {
+	s := &shardedTagList[T]{
+		shards:  make([]*guardedTagList[T], maxInt(shards, 1)),
+		options: options,
+	}
+
+	for i := range s.shards {
+		s.shards[i] = &guardedTagList[T]{
+			mapList: make(map[string]GuardedPointerList[T]),
+			options: options,
+		}
+	}
+
+	return s
+}
+
+//Encodes the ShardedTagList as a JSON object keyed by tag.
+func (s *shardedTagList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToArrayMap())
+}
+
+//Replaces the tags of the ShardedTagList with the decoded JSON object.
+func (s *shardedTagList[T]) UnmarshalJSON(data []byte) error {
+	items := make(map[string][]*T)
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	s.replaceMap(items)
+
+	return nil
+}
+
+//Writes a snapshot of the ShardedTagList using GobCodec[T].
+func (s *shardedTagList[T]) WriteTo(w io.Writer) (int64, error) {
+	return WriteTagSnapshot[T](w, s, GobCodec[T]{})
+}
+
+//Replaces the tags of the ShardedTagList with a snapshot written by WriteTo.
+func (s *shardedTagList[T]) ReadFrom(r io.Reader) (int64, error) {
+	return ReadTagSnapshot[T](r, s, GobCodec[T]{})
+}
+
+//Writes the ShardedTagList as CSV with a leading "tag" column, ordered by key.
+func (s *shardedTagList[T]) ExportCSV(w io.Writer, columns ...Column[T]) error {
+	return writeTagCSV[T](w, ',', s, columns)
+}
+
+//Adds the rows of a CSV with a leading "tag" column to the ShardedTagList. The limits of the tags are checked before
+//the first row is added, so nothing is added if a row does not fit.
+func (s *shardedTagList[T]) ImportCSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, ',', build, s.fits, s.Add)
+}
+
+//Same as ExportCSV, separated by tabs.
+func (s *shardedTagList[T]) ExportTSV(w io.Writer, columns ...Column[T]) error {
+	return writeTagCSV[T](w, '\t', s, columns)
+}
+
+//Same as ImportCSV, separated by tabs.
+func (s *shardedTagList[T]) ImportTSV(r io.Reader, build CSVBuildFunc[T]) error {
+	return readTagCSV(r, '\t', build, s.fits, s.Add)
+}
+
+//Returns a new map holding the lists of every shard.
+func (s *shardedTagList[T]) ToMap() map[string]GuardedPointerList[T] {
+	var mapList map[string]GuardedPointerList[T]
+
+	s.view(func(l *guardedTagList[T]) {
+		mapList = l.mapList
+	})
+
+	return mapList
+}
+
+func (s *shardedTagList[T]) Get(key string) GuardedPointerList[T] {
+	return s.shard(key).Get(key)
+}
+
+func (s *shardedTagList[T]) GetNext(key string) *T {
+	return s.shard(key).GetNext(key)
+}
+
+//Adds a tag with the specified key and value to the list. A tag at its limit returns a KeyLimit error
+//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+//that rejected the element, nothing is removed then.
+func (s *shardedTagList[T]) Add(key string, value *T) (*T, error) {
+	return s.shard(key).Add(key, value)
+}
+
+//Removes all elements from the ShardedTagList.
+func (s *shardedTagList[T]) Clear() {
+	s.lockAll()
+	defer s.unlockAll()
+
+	for _, shard := range s.shards {
+		shard.retireAll()
+		shard.mapList = make(map[string]GuardedPointerList[T])
+	}
+}
+
+//Removes target list from the ShardedTagList.
+func (s *shardedTagList[T]) ClearList(key string) {
+	s.shard(key).ClearList(key)
+}
+
+//Determines whether a tag is in the ShardedTagList.
+func (s *shardedTagList[T]) Contains(key string, value *T) bool {
+	return s.shard(key).Contains(key, value)
+}
+
+//Inserts an element into the ShardedTagList at the specified index. A tag at its limit returns a KeyLimit error
+//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+//that rejected the element, nothing is removed then.
+func (s *shardedTagList[T]) Insert(index int, key string, value *T) (*T, error) {
+	return s.shard(key).Insert(index, key, value)
+}
+
+//Removes the first occurrence of a specific object from the ShardedTagList.
+func (s *shardedTagList[T]) Remove(key string, value *T) bool {
+	return s.shard(key).Remove(key, value)
+}
+
+//Removes the element at the specified index of the ShardedTagList.
+func (s *shardedTagList[T]) RemoveAt(key string, index int) bool {
+	return s.shard(key).RemoveAt(key, index)
+}
+
+//Returns the number of elements in a sequence.
+func (s *shardedTagList[T]) Count() int {
+	count := 0
+
+	s.view(func(l *guardedTagList[T]) {
+		count = l.Count()
+	})
+
+	return count
+}
+
+//Total count
+func (s *shardedTagList[T]) TotalCount() int {
+	count := 0
+
+	s.view(func(l *guardedTagList[T]) {
+		count = l.TotalCount()
+	})
+
+	return count
+}
+
+//Returns the number of elements in a sequence.
+func (s *shardedTagList[T]) MapCount() map[string]int {
+	var count map[string]int
+
+	s.view(func(l *guardedTagList[T]) {
+		count = l.MapCount()
+	})
+
+	return count
+}
+
+//Returns the number of elements and the limit of every tag.
+func (s *shardedTagList[T]) MapCountLimit() map[string]KeyCount {
+	s.lockAll()
+	defer s.unlockAll()
+
+	count := make(map[string]KeyCount)
+
+	for _, shard := range s.shards {
+		for key, list := range shard.mapList {
+			limit := shard.limitOf(key)
+			count[key] = KeyCount{Count: countOf[T](list), Limit: limit.limit, Policy: limit.policy}
+		}
+	}
+
+	return count
+}
+
+//Limits the number of elements of the tag, n < 1 removes the limit of the tag so the default limit applies.
+func (s *shardedTagList[T]) SetKeyLimit(key string, n int, policy LimitPolicy) {
+	s.shard(key).SetKeyLimit(key, n, policy)
+}
+
+//Limits the number of elements of every tag without a SetKeyLimit, n < 1 removes the limit.
+func (s *shardedTagList[T]) SetDefaultLimit(n int, policy LimitPolicy) {
+	s.lockAll()
+	defer s.unlockAll()
+
+	for _, shard := range s.shards {
+		shard.defaultLimit = keyLimit{limit: maxInt(n, 0), policy: policy}
+	}
+}
+
+//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]
+func (s *shardedTagList[T]) MapCountSelect(f CountSelectTagListFunc[T]) map[string]int {
+	var count map[string]int
+
+	s.view(func(l *guardedTagList[T]) {
+		count = l.MapCountSelect(f)
+	})
+
+	return count
+}
+
+//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]
+func (s *shardedTagList[T]) CountSelect(f CountSelectTagListFunc[T]) int {
+	count := 0
+
+	s.view(func(l *guardedTagList[T]) {
+		count = l.CountSelect(f)
+	})
+
+	return count
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate, and returns the first occurrence within the entire PointerList[T].
+func (s *shardedTagList[T]) Find(f FindPointerFunc[T]) *T {
+	var item *T
+
+	s.view(func(l *guardedTagList[T]) {
+		item = l.Find(f)
+	})
+
+	return item
+}
+
+func (s *shardedTagList[T]) GetNextBefore(key string, f BeforeListFunc[T]) *T {
+	return s.shard(key).GetNextBefore(key, f)
+}
+
+//Adds a tag with the specified key and value to the list. Returns false if the lock of the shard could not be taken within timeout,
+//or false and a KeyLimit error if the tag is at its limit with LimitReject.
+func (s *shardedTagList[T]) TryAdd(key string, value *T, timeout time.Duration) (bool, error) {
+	return s.shard(key).TryAdd(key, value, timeout)
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns false if the locks could not be taken within timeout.
+func (s *shardedTagList[T]) TryFind(f FindPointerFunc[T], timeout time.Duration) (*T, bool) {
+	deadline := time.Now().Add(timeout)
+
+	if !s.tryLockAll(timeout) {
+		return nil, false
+	}
+	defer s.unlockAll()
+
+	return s.merged().TryFind(f, time.Until(deadline))
+}
+
+//Loop
+func (s *shardedTagList[T]) Foreach(f ForeachTagListFunc[T]) {
+	s.view(func(l *guardedTagList[T]) {
+		l.Foreach(f)
+	})
+}
+
+//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+func (s *shardedTagList[T]) MapCountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (map[string]int, error) {
+	var count map[string]int
+	var err error
+
+	s.view(func(l *guardedTagList[T]) {
+		count, err = l.MapCountSelectCtx(ctx, f)
+	})
+
+	return count, err
+}
+
+//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+func (s *shardedTagList[T]) CountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (int, error) {
+	count := 0
+	var err error
+
+	s.view(func(l *guardedTagList[T]) {
+		count, err = l.CountSelectCtx(ctx, f)
+	})
+
+	return count, err
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+func (s *shardedTagList[T]) FindCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error) {
+	var item *T
+	var err error
+
+	s.view(func(l *guardedTagList[T]) {
+		item, err = l.FindCtx(ctx, f)
+	})
+
+	return item, err
+}
+
+//Loop. Returns ctx.Err() if the context is done.
+func (s *shardedTagList[T]) ForeachCtx(ctx context.Context, f ForeachTagListFunc[T]) error {
+	var err error
+
+	s.view(func(l *guardedTagList[T]) {
+		err = l.ForeachCtx(ctx, f)
+	})
+
+	return err
+}
+
+//Adds a tag with the specified key and value that is removed once d passed without a Touch.
+//Nothing is added if the tag is at its limit with LimitReject.
+func (s *shardedTagList[T]) AddWithTTL(key string, value *T, d time.Duration) error {
+	return s.shard(key).AddWithTTL(key, value, d)
+}
+
+//Restarts the time-to-live of an element added by AddWithTTL. Returns false if it has none.
+func (s *shardedTagList[T]) Touch(key string, value *T) bool {
+	return s.shard(key).Touch(key, value)
+}
+
+//Reverts the last step of the tag. Returns false if there is none or the tag has no history.
+func (s *shardedTagList[T]) Undo(key string) bool {
+	return s.shard(key).Undo(key)
+}
+
+//Applies the last reverted step of the tag again. Returns false if there is none or the tag has no history.
+func (s *shardedTagList[T]) Redo(key string) bool {
+	return s.shard(key).Redo(key)
+}
+
+//Determines whether there is a step of the tag to Undo.
+func (s *shardedTagList[T]) CanUndo(key string) bool {
+	return s.shard(key).CanUndo(key)
+}
+
+//Determines whether there is a step of the tag to Redo.
+func (s *shardedTagList[T]) CanRedo(key string) bool {
+	return s.shard(key).CanRedo(key)
+}
+
+//Names the current state of the tag for RevertTo.
+func (s *shardedTagList[T]) Checkpoint(key string, name string) {
+	s.shard(key).Checkpoint(key, name)
+}
+
+//Undoes or redoes steps of the tag until it is in the state named by Checkpoint.
+func (s *shardedTagList[T]) RevertTo(key string, name string) error {
+	return s.shard(key).RevertTo(key, name)
+}
+
+//Calls f and records the changes it makes to the tag as one step. f is called without the shard lock.
+func (s *shardedTagList[T]) Batch(key string, f func()) {
+	s.shard(key).Batch(key, f)
+}
+
+//Returns a number increased by every change of the tag, 0 for a tag that never had a list.
+func (s *shardedTagList[T]) Version(key string) uint64 {
+	return s.shard(key).Version(key)
+}
+
+//Replaces the elements of the tag with a copy of items if the tag is still at version, else returns ErrConflict.
+func (s *shardedTagList[T]) ReplaceIfVersion(key string, version uint64, items []*T) error {
+	return s.shard(key).ReplaceIfVersion(key, version, items)
+}
+
+//Replaces the element of the tag at the specified index if the tag is still at version, else returns ErrConflict.
+func (s *shardedTagList[T]) SetIfVersion(key string, version uint64, index int, value *T) error {
+	return s.shard(key).SetIfVersion(key, version, index, value)
+}
+
+//Adds a tag with the specified key and value if the tag is still at version, else returns ErrConflict.
+//A tag at its limit returns a KeyLimit error whatever its LimitPolicy.
+func (s *shardedTagList[T]) AddIfVersion(key string, version uint64, value *T) error {
+	return s.shard(key).AddIfVersion(key, version, value)
+}
+
+//Sets the func called with every expired element, after the shards were released, so it may use the ShardedTagList.
+func (s *shardedTagList[T]) OnExpire(f ExpireTagFunc[T]) {
+	for _, shard := range s.shards {
+		shard.OnExpire(f)
+	}
+}
+
+//Starts a goroutine removing the expired elements of every shard every interval, until Close.
+func (s *shardedTagList[T]) StartJanitor(interval time.Duration) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	clock := newPointerList[T](nil, nil, s.options).clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	s.stop = make(chan struct{})
+	go runJanitor(clock, interval, s.stop, s.purge)
+}
+
+//Stops the janitor.
+func (s *shardedTagList[T]) Close() error {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+
+	return nil
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//Copies the elements of every tag with the shards locked in order.
+func (s *shardedTagList[T]) ToArrayMap() map[string][]*T {
+	s.lockAll()
+	defer s.unlockAll()
+
+	items := make(map[string][]*T)
+
+	for _, shard := range s.shards {
+		for key, list := range shard.mapList {
+			if list != nil {
+				items[key] = snapshotOf[T](list)
+			} else {
+				items[key] = nil
+			}
+		}
+	}
+
+	return items
+}
+
+//Replaces every tag with the specified elements with the shards locked in order.
+func (s *shardedTagList[T]) replaceMap(items map[string][]*T) {
+	mapLists := make([]map[string]GuardedPointerList[T], len(s.shards))
+
+	for i := range mapLists {
+		mapLists[i] = make(map[string]GuardedPointerList[T])
+	}
+
+	for key, list := range items {
+		mapList := mapLists[s.shardIndex(key)]
+
+		if list != nil {
+			mapList[key] = newPointerList(list, &lockerBase{}, s.options)
+		} else {
+			mapList[key] = nil
+		}
+	}
+
+	s.lockAll()
+	defer s.unlockAll()
+
+	for i, shard := range s.shards {
+		shard.retireAll()
+		shard.mapList = mapLists[i]
+	}
+}
+
+//Removes the expired elements of every shard, one shard at a time.
+func (s *shardedTagList[T]) purge() {
+	for _, shard := range s.shards {
+		shard.purge()
+	}
+}
+
+//Checks the counts of every tag against the limits of its shard.
+func (s *shardedTagList[T]) fits(counts map[string]int) error {
+	for key, n := range counts {
+		if err := s.shard(key).fits(map[string]int{key: n}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *shardedTagList[T]) shard(key string) *guardedTagList[T] {
+	return s.shards[s.shardIndex(key)]
+}
+
+//FNV-1a hash of the key.
+func (s *shardedTagList[T]) shardIndex(key string) int {
+	hash := uint32(2166136261)
+
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+
+	return int(hash % uint32(len(s.shards)))
+}
+
+//Runs f on a GuardedTagList holding the lists of every shard, with the shards locked in order.
+//f must not add or remove tags of l, the lists are shared.
+func (s *shardedTagList[T]) view(f func(l *guardedTagList[T])) {
+	s.lockAll()
+	defer s.unlockAll()
+
+	f(s.merged())
+}
+
+//The shards are locked.
+func (s *shardedTagList[T]) merged() *guardedTagList[T] {
+	l := &guardedTagList[T]{
+		mapList: make(map[string]GuardedPointerList[T]),
+		options: s.options,
+	}
+
+	for _, shard := range s.shards {
+		for key, list := range shard.mapList {
+			l.mapList[key] = list
+		}
+	}
+
+	return l
+}
+
+func (s *shardedTagList[T]) lockAll() {
+	for _, shard := range s.shards {
+		shard.lock()
+	}
+}
+
+//Releases the shards, then calls the OnExpire funcs held back by any of them.
+func (s *shardedTagList[T]) unlockAll() {
+	var expired []func()
+	for _, shard := range s.shards {
+		expired = append(expired, shard.expiring.take()...)
+	}
+
+	for i := len(s.shards) - 1; i >= 0; i-- {
+		s.shards[i].locker.Unlock()
+	}
+
+	callAll(expired)
+}
+
+//Locks the shards in order within timeout. Reports false with none of them locked.
+func (s *shardedTagList[T]) tryLockAll(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	for i, shard := range s.shards {
+		if !shard.lockWithin(time.Until(deadline)) {
+			for j := i - 1; j >= 0; j-- {
+				s.shards[j].unlock()
+			}
+
+			return false
+		}
+	}
+
+	return true
+}
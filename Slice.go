@@ -0,0 +1,192 @@
+package PointerList
+
+import "context"
+
+/////////////////////////////////
+//            Slice            //
+/////////////////////////////////
+
+//Algorithms shared by pointerList and valueList, so both lists behave the same way.
+
+//Returns the element at lastIndex and moves lastIndex forward, starting over at the end of the list.
+func sliceGetNext[E any](list []E, lastIndex *int) (E, bool) {
+	var zero E
+
+	if len(list) == 0 {
+		return zero, false
+	}
+
+	if *lastIndex >= len(list) {
+		*lastIndex = 0
+	}
+
+	selectedItem := list[*lastIndex]
+	*lastIndex++
+
+	return selectedItem, true
+}
+
+func sliceInsert[E any](list []E, targetItem E, targetIndex int) ([]E, error) {
+	if targetIndex > len(list) || targetIndex < 0 {
+		return list, GetErrorf(IndexOutOfRange, len(list), len(list))
+	}
+
+	newArray := make([]E, len(list)+1)
+
+	for i, index := 0, 0; i < len(newArray); i, index = i+1, index+1 {
+		if i == targetIndex {
+			newArray[i] = targetItem
+			index--
+		} else {
+			newArray[i] = list[index]
+		}
+	}
+
+	return newArray, nil
+}
+
+func sliceInsertRange[E any](list []E, targetItems []E, targetIndex int) ([]E, error) {
+	if targetIndex > len(list) || targetIndex < 0 {
+		return list, GetErrorf(IndexOutOfRange, len(list), len(list))
+	} else if len(targetItems) == 0 {
+		return list, nil
+	}
+
+	newArray := make([]E, len(list)+len(targetItems))
+
+	for i, index := 0, 0; i < len(newArray); i, index = i+1, index+1 {
+		if i == targetIndex {
+			for i2 := 0; i2 < len(targetItems); i2++ {
+				newArray[i+i2] = targetItems[i2]
+			}
+			i += len(targetItems) - 1
+
+			index--
+		} else {
+			newArray[i] = list[index]
+		}
+	}
+
+	return newArray, nil
+}
+
+func sliceRemoveAt[E any](list []E, index int) ([]E, bool) {
+	if index >= len(list) || index < 0 {
+		return list, false
+	}
+
+	return append(list[:index], list[index+1:]...), true
+}
+
+//f gets the index the element had before the walk, removed gets its current index.
+func sliceRemoveAll[E any](list []E, f func(current E, index int) bool, removed func(current E, index int)) []E {
+	for i, i2 := 0, 0; i < len(list); i, i2 = i+1, i2+1 {
+		if f(list[i], i2) {
+			if removed != nil {
+				removed(list[i], i)
+			}
+
+			list = append(list[:i], list[i+1:]...)
+			i--
+		}
+	}
+
+	return list
+}
+
+func sliceReverse[E any](list []E) {
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+}
+
+func sliceSort[E any](list []E, f func(left E, right E) bool) {
+	_ = sliceSortCtx(context.Background(), list, f)
+}
+
+//Same as sliceSort. Returns ctx.Err() if the context is done, the list is then partly sorted.
+func sliceSortCtx[E any](ctx context.Context, list []E, f func(left E, right E) bool) error {
+	for i2 := 0; i2 < len(list); i2++ {
+		//Every pass visits the whole list.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		for i := 0; i < len(list); i++ {
+			if f(list[i], list[i2]) {
+				list[i], list[i2] = list[i2], list[i]
+			}
+			/*if l.list[i] > l.list[i2] {
+				l.list[i], l.list[i2] = l.list[i2], l.list[i]
+			}*/
+		}
+	}
+
+	return nil
+}
+
+//Returns the index of the first element that matches the predicate, or -1.
+func sliceFindIndex[E any](list []E, f func(index int, current E) bool) int {
+	for i := 0; i < len(list); i++ {
+		if f(i, list[i]) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func sliceFindAll[E any](list []E, f func(index int, current E) bool) []E {
+	retList := make([]E, 0)
+
+	for i := 0; i < len(list); i++ {
+		if f(i, list[i]) {
+			retList = append(retList, list[i])
+		}
+	}
+
+	return retList
+}
+
+func sliceTrueForAll[E any](list []E, f func(current E) bool) bool {
+	for i := 0; i < len(list); i++ {
+		if !f(list[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sliceForeach[E any](list []E, f func(index int, current E) bool) {
+	for i := 0; i < len(list); i++ {
+		if !f(i, list[i]) {
+			break
+		}
+	}
+}
+
+//Sets every element to its zero value, so the array no longer references them.
+func sliceClear[E any](list []E) {
+	var zero E
+
+	for i := range list {
+		list[i] = zero
+	}
+}
+
+func minInt(left int, right int) int {
+	if left < right {
+		return left
+	}
+
+	return right
+}
+
+func maxInt(left int, right int) int {
+	if left > right {
+		return left
+	}
+
+	return right
+}
@@ -0,0 +1,807 @@
+package PointerList
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"sort"
+)
+
+/////////////////////////////////
+//           Snapshot          //
+/////////////////////////////////
+
+//Snapshot layout:
+//	magic "GPLS" | version (1 byte) | kind (1 byte) | object table | payload | crc32 of everything before it (4 bytes, big endian)
+//Object table:
+//	uvarint count | count * (uvarint length | Codec[T] bytes)
+//List payload:
+//	uvarint count | count * ref
+//Tag payload:
+//	uvarint tag count | tag count * (uvarint key length | key | uvarint count+1, 0 => cleared list | count * ref)
+//Bundle payload:
+//	uvarint entry count | entry count * (uvarint name length | name | kind (1 byte) | list or tag payload)
+//Ref:
+//	uvarint, 0 => nil, n => n. object of the table. Every distinct pointer is stored once, so elements shared
+//	between tags and lists are shared again after restore.
+//Version 1 has no object table and no bundles, every ref is an inline item: uvarint length+1, 0 => nil | Codec[T] bytes.
+const (
+	snapshotMagic   = "GPLS"
+	snapshotVersion = 2
+
+	snapshotKindList   byte = 1
+	snapshotKindTags   byte = 2
+	snapshotKindBundle byte = 3
+)
+
+//Encodes and decodes single elements of a snapshot.
+type Codec[T any] interface {
+	//Encodes a non-nil element.
+	Marshal(item *T) ([]byte, error)
+	//Decodes an element encoded by Marshal.
+	Unmarshal(data []byte) (*T, error)
+}
+
+//Default Codec[T] of WriteTo/ReadFrom, based on encoding/gob.
+type GobCodec[T any] struct{}
+
+func (GobCodec[T]) Marshal(item *T) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := gob.NewEncoder(buf).Encode(item); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (GobCodec[T]) Unmarshal(data []byte) (*T, error) {
+	item := new(T)
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+//Writes a snapshot of the PointerList[T] using the specified Codec[T].
+func WriteSnapshot[T any](w io.Writer, l PointerList[T], codec Codec[T]) (int64, error) {
+	objects := newSnapshotObjects[T]()
+	refs := objects.refs(snapshotOf[T](l))
+
+	s := newSnapshotWriter(w, snapshotKindList)
+
+	if err := writeSnapshotObjects(s, objects, codec); err != nil {
+		return s.n, err
+	}
+
+	s.writeRefs(refs)
+
+	return s.finish()
+}
+
+//Replaces the elements of the PointerList[T] with a snapshot written by WriteSnapshot. The list is not changed if the snapshot is rejected.
+func ReadSnapshot[T any](r io.Reader, l PointerList[T], codec Codec[T]) (int64, error) {
+	s := newSnapshotReader(r)
+
+	if err := s.readHeader(snapshotKindList); err != nil {
+		return s.n, err
+	}
+
+	refs, err := s.readRefs()
+	if err != nil {
+		return s.n, err
+	}
+
+	objects, err := finishSnapshot(s, codec)
+	if err != nil {
+		return s.n, err
+	}
+
+	replaceOf[T](l, resolveSnapshotRefs(objects, refs))
+
+	return s.n, nil
+}
+
+//Writes a snapshot of the TagList or GuardedTagList using the specified Codec[T].
+func WriteTagSnapshot[T any](w io.Writer, l TagSource[T], codec Codec[T]) (int64, error) {
+	_, n, err := writeTagSnapshot(w, l.ToArrayMap(), codec)
+	return n, err
+}
+
+//Replaces the tags of the TagList or GuardedTagList with a snapshot written by WriteTagSnapshot. The list is not changed if the snapshot is rejected.
+func ReadTagSnapshot[T any](r io.Reader, l TagSource[T], codec Codec[T]) (int64, error) {
+	items, _, n, err := readTagSnapshot(r, codec)
+	if err != nil {
+		return n, err
+	}
+
+	return n, replaceTagsOf(l, items)
+}
+
+/////////////////////////////////
+//        Snapshot Bundle      //
+/////////////////////////////////
+
+//Several lists and tag lists saved into one snapshot. An element shared between them is stored once
+//and is shared again after restore.
+type SnapshotBundle[T any] struct {
+	codec   Codec[T]
+	entries []snapshotEntry[T]
+}
+
+type snapshotEntry[T any] struct {
+	name string
+	list PointerList[T]
+	tags TagSource[T]
+}
+
+//Creates an empty bundle. A nil codec uses GobCodec[T].
+func NewSnapshotBundle[T any](codec Codec[T]) *SnapshotBundle[T] {
+	if codec == nil {
+		codec = GobCodec[T]{}
+	}
+
+	return &SnapshotBundle[T]{
+		codec: codec,
+	}
+}
+
+//Adds the PointerList[T] to the bundle. It is written by WriteTo and replaced by ReadFrom.
+func (b *SnapshotBundle[T]) AddList(name string, l PointerList[T]) {
+	b.add(snapshotEntry[T]{name: name, list: l})
+}
+
+//Adds the TagList or GuardedTagList to the bundle. It is written by WriteTo and replaced by ReadFrom.
+func (b *SnapshotBundle[T]) AddTags(name string, l TagSource[T]) {
+	b.add(snapshotEntry[T]{name: name, tags: l})
+}
+
+//Writes every list of the bundle. Each list is copied under its own lock.
+func (b *SnapshotBundle[T]) WriteTo(w io.Writer) (int64, error) {
+	objects := newSnapshotObjects[T]()
+	listRefs := make([][]uint64, len(b.entries))
+	tagRefs := make([]map[string][]uint64, len(b.entries))
+
+	for i, entry := range b.entries {
+		if entry.list != nil {
+			listRefs[i] = objects.refs(snapshotOf[T](entry.list))
+		} else {
+			tagRefs[i] = objects.tagRefs(entry.tags.ToArrayMap())
+		}
+	}
+
+	s := newSnapshotWriter(w, snapshotKindBundle)
+
+	if err := writeSnapshotObjects(s, objects, b.codec); err != nil {
+		return s.n, err
+	}
+
+	s.writeUvarint(uint64(len(b.entries)))
+
+	for i, entry := range b.entries {
+		s.writeBytes([]byte(entry.name))
+
+		if entry.list != nil {
+			s.write([]byte{snapshotKindList})
+			s.writeRefs(listRefs[i])
+		} else {
+			s.write([]byte{snapshotKindTags})
+			s.writeTagRefs(tagRefs[i])
+		}
+	}
+
+	return s.finish()
+}
+
+//Replaces every list of the bundle that is found in the snapshot. Lists of the snapshot that were not added are skipped.
+//No list is changed if the snapshot is rejected.
+func (b *SnapshotBundle[T]) ReadFrom(r io.Reader) (int64, error) {
+	s := newSnapshotReader(r)
+
+	if err := s.readHeader(snapshotKindBundle); err != nil {
+		return s.n, err
+	}
+
+	count, err := s.readUvarint()
+	if err != nil {
+		return s.n, err
+	}
+
+	listRefs := make(map[string][]uint64)
+	tagRefs := make(map[string]map[string][]uint64)
+
+	for i := uint64(0); i < count; i++ {
+		name, err := s.readBytes()
+		if err != nil {
+			return s.n, err
+		}
+
+		kind, err := s.ReadByte()
+		if err != nil {
+			return s.n, snapshotReadError(err)
+		}
+
+		switch kind {
+		case snapshotKindList:
+			listRefs[string(name)], err = s.readRefs()
+		case snapshotKindTags:
+			tagRefs[string(name)], err = s.readTagRefs()
+		default:
+			err = GetError(InvalidSnapshot)
+		}
+
+		if err != nil {
+			return s.n, err
+		}
+	}
+
+	objects, err := finishSnapshot(s, b.codec)
+	if err != nil {
+		return s.n, err
+	}
+
+	for _, entry := range b.entries {
+		_, isList := listRefs[entry.name]
+		_, isTags := tagRefs[entry.name]
+
+		if (entry.list != nil && isTags) || (entry.tags != nil && isList) {
+			return s.n, GetError(InvalidSnapshot)
+		}
+	}
+
+	for _, entry := range b.entries {
+		if refs, ok := listRefs[entry.name]; ok {
+			replaceOf[T](entry.list, resolveSnapshotRefs(objects, refs))
+		} else if refs, ok := tagRefs[entry.name]; ok {
+			if err := replaceTagsOf(entry.tags, resolveSnapshotTagRefs(objects, refs)); err != nil {
+				return s.n, err
+			}
+		}
+	}
+
+	return s.n, nil
+}
+
+//An entry with the same name is replaced.
+func (b *SnapshotBundle[T]) add(entry snapshotEntry[T]) {
+	for i := 0; i < len(b.entries); i++ {
+		if b.entries[i].name == entry.name {
+			b.entries[i] = entry
+			return
+		}
+	}
+
+	b.entries = append(b.entries, entry)
+}
+
+//Writes a snapshot of the PointerList[T] using GobCodec[T].
+func (l *pointerList[T]) WriteTo(w io.Writer) (int64, error) {
+	return WriteSnapshot[T](w, l, GobCodec[T]{})
+}
+
+//Replaces the elements of the PointerList[T] with a snapshot written by WriteTo.
+func (l *pointerList[T]) ReadFrom(r io.Reader) (int64, error) {
+	return ReadSnapshot[T](r, l, GobCodec[T]{})
+}
+
+//Writes a snapshot of the TagList using GobCodec[T].
+func (l *tagList[T]) WriteTo(w io.Writer) (int64, error) {
+	return WriteTagSnapshot[T](w, l, GobCodec[T]{})
+}
+
+//Replaces the tags of the TagList with a snapshot written by WriteTo.
+func (l *tagList[T]) ReadFrom(r io.Reader) (int64, error) {
+	return ReadTagSnapshot[T](r, l, GobCodec[T]{})
+}
+
+//Writes a snapshot of the GuardedTagList using GobCodec[T].
+func (l *guardedTagList[T]) WriteTo(w io.Writer) (int64, error) {
+	return WriteTagSnapshot[T](w, l, GobCodec[T]{})
+}
+
+//Replaces the tags of the GuardedTagList with a snapshot written by WriteTo.
+func (l *guardedTagList[T]) ReadFrom(r io.Reader) (int64, error) {
+	return ReadTagSnapshot[T](r, l, GobCodec[T]{})
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//Upper bound of the capacity allocated from a length read from a snapshot, larger lists grow while reading.
+const snapshotMaxPrealloc = 1024
+
+func snapshotPrealloc(count uint64) int {
+	if count > snapshotMaxPrealloc {
+		return snapshotMaxPrealloc
+	}
+
+	return int(count)
+}
+
+//Distinct elements of a snapshot in the order of first appearance.
+type snapshotObjects[T any] struct {
+	ids   map[*T]uint64
+	items []*T
+}
+
+func newSnapshotObjects[T any]() *snapshotObjects[T] {
+	return &snapshotObjects[T]{
+		ids: make(map[*T]uint64),
+	}
+}
+
+func (o *snapshotObjects[T]) ref(item *T) uint64 {
+	if item == nil {
+		return 0
+	}
+
+	if id, ok := o.ids[item]; ok {
+		return id
+	}
+
+	o.items = append(o.items, item)
+	o.ids[item] = uint64(len(o.items))
+
+	return uint64(len(o.items))
+}
+
+func (o *snapshotObjects[T]) refs(items []*T) []uint64 {
+	refs := make([]uint64, len(items))
+
+	for i := 0; i < len(items); i++ {
+		refs[i] = o.ref(items[i])
+	}
+
+	return refs
+}
+
+func (o *snapshotObjects[T]) tagRefs(items map[string][]*T) map[string][]uint64 {
+	refs := make(map[string][]uint64, len(items))
+
+	for _, key := range snapshotKeys(items) {
+		list := items[key]
+
+		if list != nil {
+			refs[key] = o.refs(list)
+		} else {
+			refs[key] = nil
+		}
+	}
+
+	return refs
+}
+
+//Returns the distinct objects of the snapshot, the object with ref n has the index n-1.
+func writeTagSnapshot[T any](w io.Writer, items map[string][]*T, codec Codec[T]) (*snapshotObjects[T], int64, error) {
+	objects := newSnapshotObjects[T]()
+	refs := objects.tagRefs(items)
+
+	s := newSnapshotWriter(w, snapshotKindTags)
+
+	if err := writeSnapshotObjects(s, objects, codec); err != nil {
+		return nil, s.n, err
+	}
+
+	s.writeTagRefs(refs)
+
+	n, err := s.finish()
+	return objects, n, err
+}
+
+//Returns the tags and the distinct objects of the snapshot, the object with ref n has the index n-1.
+func readTagSnapshot[T any](r io.Reader, codec Codec[T]) (map[string][]*T, []*T, int64, error) {
+	s := newSnapshotReader(r)
+
+	if err := s.readHeader(snapshotKindTags); err != nil {
+		return nil, nil, s.n, err
+	}
+
+	refs, err := s.readTagRefs()
+	if err != nil {
+		return nil, nil, s.n, err
+	}
+
+	objects, err := finishSnapshot(s, codec)
+	if err != nil {
+		return nil, nil, s.n, err
+	}
+
+	return resolveSnapshotTagRefs(objects, refs), objects, s.n, nil
+}
+
+func writeSnapshotObjects[T any](s *snapshotWriter, objects *snapshotObjects[T], codec Codec[T]) error {
+	s.writeUvarint(uint64(len(objects.items)))
+
+	for i := 0; i < len(objects.items); i++ {
+		data, err := codec.Marshal(objects.items[i])
+		if err != nil {
+			return err
+		}
+
+		s.writeBytes(data)
+	}
+
+	return s.err
+}
+
+func decodeSnapshotObjects[T any](records [][]byte, codec Codec[T]) ([]*T, error) {
+	objects := make([]*T, len(records))
+
+	for i := 0; i < len(records); i++ {
+		item, err := codec.Unmarshal(records[i])
+		if err != nil {
+			return nil, err
+		}
+
+		objects[i] = item
+	}
+
+	return objects, nil
+}
+
+func resolveSnapshotRefs[T any](objects []*T, refs []uint64) []*T {
+	items := make([]*T, len(refs))
+
+	for i := 0; i < len(refs); i++ {
+		if refs[i] != 0 {
+			items[i] = objects[refs[i]-1]
+		}
+	}
+
+	return items
+}
+
+func resolveSnapshotTagRefs[T any](objects []*T, refs map[string][]uint64) map[string][]*T {
+	items := make(map[string][]*T, len(refs))
+
+	for key, list := range refs {
+		if list != nil {
+			items[key] = resolveSnapshotRefs(objects, list)
+		} else {
+			items[key] = nil
+		}
+	}
+
+	return items
+}
+
+type snapshotWriter struct {
+	w   io.Writer
+	crc hash.Hash32
+	n   int64
+	err error
+}
+
+func newSnapshotWriter(w io.Writer, kind byte) *snapshotWriter {
+	s := &snapshotWriter{
+		w:   w,
+		crc: crc32.NewIEEE(),
+	}
+
+	s.write([]byte(snapshotMagic))
+	s.write([]byte{snapshotVersion, kind})
+
+	return s
+}
+
+func (s *snapshotWriter) write(data []byte) {
+	if s.err != nil {
+		return
+	}
+
+	n, err := s.w.Write(data)
+	s.n += int64(n)
+	s.crc.Write(data[:n])
+	s.err = err
+}
+
+//Appends the value to the buffer as a uvarint.
+func appendUvarint(buf []byte, value uint64) []byte {
+	var varint [binary.MaxVarintLen64]byte
+
+	return append(buf, varint[:binary.PutUvarint(varint[:], value)]...)
+}
+
+func (s *snapshotWriter) writeUvarint(value uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	s.write(buf[:binary.PutUvarint(buf, value)])
+}
+
+//Writes a length prefixed byte slice.
+func (s *snapshotWriter) writeBytes(data []byte) {
+	s.writeUvarint(uint64(len(data)))
+	s.write(data)
+}
+
+func (s *snapshotWriter) writeRefs(refs []uint64) {
+	s.writeUvarint(uint64(len(refs)))
+
+	for i := 0; i < len(refs); i++ {
+		s.writeUvarint(refs[i])
+	}
+}
+
+func (s *snapshotWriter) writeTagRefs(refs map[string][]uint64) {
+	s.writeUvarint(uint64(len(refs)))
+
+	for _, key := range snapshotKeys(refs) {
+		list := refs[key]
+		s.writeBytes([]byte(key))
+
+		if list == nil {
+			s.writeUvarint(0)
+			continue
+		}
+
+		s.writeUvarint(uint64(len(list)) + 1)
+
+		for i := 0; i < len(list); i++ {
+			s.writeUvarint(list[i])
+		}
+	}
+}
+
+//Returns the tags in order, so that the same tag list always writes the same snapshot.
+func snapshotKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+//Writes the checksum.
+func (s *snapshotWriter) finish() (int64, error) {
+	sum := make([]byte, 4)
+	binary.BigEndian.PutUint32(sum, s.crc.Sum32())
+	s.write(sum)
+
+	return s.n, s.err
+}
+
+type snapshotReader struct {
+	r       io.Reader
+	crc     hash.Hash32
+	n       int64
+	version byte
+	//Encoded objects, filled from the object table or from the inline items of version 1.
+	records [][]byte
+}
+
+func newSnapshotReader(r io.Reader) *snapshotReader {
+	return &snapshotReader{
+		r:   r,
+		crc: crc32.NewIEEE(),
+	}
+}
+
+func (s *snapshotReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.n += int64(n)
+	s.crc.Write(p[:n])
+
+	return n, err
+}
+
+func (s *snapshotReader) ReadByte() (byte, error) {
+	buf := make([]byte, 1)
+
+	if _, err := io.ReadFull(s, buf); err != nil {
+		return 0, err
+	}
+
+	return buf[0], nil
+}
+
+//Reads the header and the object table.
+func (s *snapshotReader) readHeader(kind byte) error {
+	header := make([]byte, len(snapshotMagic)+2)
+
+	if _, err := io.ReadFull(s, header); err != nil {
+		return snapshotReadError(err)
+	}
+
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return GetError(InvalidSnapshot)
+	}
+
+	s.version = header[len(snapshotMagic)]
+
+	if s.version != 1 && s.version != snapshotVersion {
+		return GetError(UnsupportedSnapshotVersion)
+	}
+
+	if header[len(snapshotMagic)+1] != kind || (s.version == 1 && kind == snapshotKindBundle) {
+		return GetError(InvalidSnapshot)
+	}
+
+	if s.version == 1 {
+		return nil
+	}
+
+	count, err := s.readUvarint()
+	if err != nil {
+		return err
+	}
+
+	s.records = make([][]byte, 0, snapshotPrealloc(count))
+
+	for i := uint64(0); i < count; i++ {
+		record, err := s.readBytes()
+		if err != nil {
+			return err
+		}
+
+		s.records = append(s.records, record)
+	}
+
+	return nil
+}
+
+func (s *snapshotReader) readUvarint() (uint64, error) {
+	value, err := binary.ReadUvarint(s)
+	if err != nil {
+		return 0, snapshotReadError(err)
+	}
+
+	return value, nil
+}
+
+//Reads a length prefixed byte slice.
+func (s *snapshotReader) readBytes() ([]byte, error) {
+	length, err := s.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.readN(length)
+}
+
+func (s *snapshotReader) readRef() (uint64, error) {
+	length, err := s.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+
+	if s.version == 1 {
+		//Inline item: every element is a distinct object.
+		if length == 0 {
+			return 0, nil
+		}
+
+		record, err := s.readN(length - 1)
+		if err != nil {
+			return 0, err
+		}
+
+		s.records = append(s.records, record)
+		return uint64(len(s.records)), nil
+	}
+
+	if length > uint64(len(s.records)) {
+		return 0, GetError(InvalidSnapshot)
+	}
+
+	return length, nil
+}
+
+func (s *snapshotReader) readRefs() ([]uint64, error) {
+	count, err := s.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]uint64, 0, snapshotPrealloc(count))
+
+	for i := uint64(0); i < count; i++ {
+		ref, err := s.readRef()
+		if err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+func (s *snapshotReader) readTagRefs() (map[string][]uint64, error) {
+	tagCount, err := s.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string][]uint64)
+
+	for i := uint64(0); i < tagCount; i++ {
+		key, err := s.readBytes()
+		if err != nil {
+			return nil, err
+		}
+
+		count, err := s.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+
+		if count == 0 {
+			refs[string(key)] = nil
+			continue
+		}
+
+		list := make([]uint64, 0, snapshotPrealloc(count-1))
+
+		for i2 := uint64(0); i2 < count-1; i2++ {
+			ref, err := s.readRef()
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, ref)
+		}
+
+		refs[string(key)] = list
+	}
+
+	return refs, nil
+}
+
+//The buffer grows with the data actually read, so a corrupted length can not allocate a huge slice.
+func (s *snapshotReader) readN(length uint64) ([]byte, error) {
+	if int64(length) < 0 {
+		return nil, GetError(InvalidSnapshot)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if _, err := io.CopyN(buf, s, int64(length)); err != nil {
+		return nil, snapshotReadError(err)
+	}
+
+	return append(make([]byte, 0, buf.Len()), buf.Bytes()...), nil
+}
+
+//Compares the checksum of everything read so far with the trailing checksum.
+func (s *snapshotReader) verify() error {
+	expected := s.crc.Sum32()
+	sum := make([]byte, 4)
+
+	if _, err := io.ReadFull(s, sum); err != nil {
+		return snapshotReadError(err)
+	}
+
+	if binary.BigEndian.Uint32(sum) != expected {
+		return GetError(SnapshotChecksumMismatch)
+	}
+
+	return nil
+}
+
+//Verifies the checksum and decodes the objects.
+func finishSnapshot[T any](s *snapshotReader, codec Codec[T]) ([]*T, error) {
+	if err := s.verify(); err != nil {
+		return nil, err
+	}
+
+	return decodeSnapshotObjects(s.records, codec)
+}
+
+//A snapshot that ends early is reported as invalid.
+func snapshotReadError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return GetError(InvalidSnapshot)
+	}
+
+	return err
+}
@@ -0,0 +1,464 @@
+package PointerList
+
+import (
+	"sync"
+	"time"
+)
+
+/////////////////////////////////
+//             TTL             //
+/////////////////////////////////
+
+//Sets the Clock used for the expiry of AddWithTTL. The default is SystemClock.
+func WithClock[T any](clock Clock) ListOption[T] {
+	return func(l *pointerList[T]) {
+		l.clock = clock
+	}
+}
+
+//Adds an object to the end of the PointerList[T] that is removed once d passed without a Touch.
+//Expired elements are removed when the list is used next, or by the janitor.
+//Returns the error of the Unique index or PointerSet that rejected the element.
+func (l *pointerList[T]) AddWithTTL(item *T, d time.Duration) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if err := l.indexRejects(item); err != nil {
+		return err
+	}
+
+	l.list = append(l.list, item)
+	l.indexAdd(item, len(l.list)-1)
+	l.expiry().schedule(item, d)
+
+	return nil
+}
+
+//Restarts the time-to-live of an element added by AddWithTTL. Returns false if it has none.
+func (l *pointerList[T]) Touch(item *T) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.ttl == nil {
+		return false
+	}
+
+	return l.ttl.touch(item)
+}
+
+//Sets the func called with every expired element. It is called after the list was released, so it may use the list.
+func (l *pointerList[T]) OnExpire(f ExpireFunc[T]) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	l.expiry().onExpire = f
+}
+
+//Starts a goroutine removing the expired elements every interval, until Close.
+func (l *pointerList[T]) StartJanitor(interval time.Duration) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	l.expiry().startJanitor(interval)
+}
+
+//Stops the janitor.
+func (l *pointerList[T]) Close() error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.ttl != nil {
+		l.ttl.stopJanitor()
+	}
+
+	return nil
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//Removes the expired elements.
+func (l *pointerList[T]) purge() {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+}
+
+//Returns the expiry of the list, installing it as the hook of the BASE on first use.
+func (l *pointerList[T]) expiry() *listTTL[T] {
+	if l.ttl != nil {
+		return l.ttl
+	}
+
+	clock := l.clock
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	l.ttl = &listTTL[T]{
+		owner:   l,
+		clock:   clock,
+		expires: make(map[*T]time.Time),
+		ttl:     make(map[*T]time.Duration),
+	}
+
+	l.ttl.queue = &priorityList[T]{
+		heap:    make([]*T, 0),
+		indexOf: make(map[*T]int),
+		f: func(left *T, right *T) bool {
+			return l.ttl.expires[left].After(l.ttl.expires[right])
+		},
+	}
+
+	l.addHook(l.ttl)
+
+	return l.ttl
+}
+
+//Expiry of the elements added by AddWithTTL, called by the BASE of the owner.
+type listTTL[T any] struct {
+	owner    *pointerList[T]
+	clock    Clock
+	expires  map[*T]time.Time
+	ttl      map[*T]time.Duration
+	queue    *priorityList[T]
+	onExpire ExpireFunc[T]
+	//Removed but not yet passed to onExpire.
+	expired []*T
+	stop    chan struct{}
+}
+
+func (t *listTTL[T]) started() {
+	now := t.clock.Now()
+
+	//An expired element is not a step of the history.
+	if t.owner.history != nil {
+		t.owner.history.suspended++
+		defer func() { t.owner.history.suspended-- }()
+	}
+
+	for next := t.queue.Peek(); next != nil && !t.expires[next].After(now); next = t.queue.Peek() {
+		t.forget(next)
+
+		index := sliceFindIndex(t.owner.list, func(index int, current *T) bool {
+			return current == next
+		})
+
+		if index < 0 {
+			continue
+		}
+
+		t.owner.removeAt(index)
+
+		//Keeps GetNext on the element it would have returned.
+		if index < t.owner.lastIndex {
+			t.owner.lastIndex--
+		}
+
+		if t.onExpire != nil {
+			t.expired = append(t.expired, next)
+		}
+	}
+}
+
+func (t *listTTL[T]) released() func() {
+	if len(t.expired) == 0 {
+		return nil
+	}
+
+	items, f := t.expired, t.onExpire
+	t.expired = nil
+
+	return func() {
+		for _, item := range items {
+			f(item)
+		}
+	}
+}
+
+func (t *listTTL[T]) schedule(item *T, d time.Duration) {
+	t.ttl[item] = d
+	t.expires[item] = t.clock.Now().Add(d)
+	t.queue.Push(item)
+}
+
+func (t *listTTL[T]) touch(item *T) bool {
+	d, ok := t.ttl[item]
+	if !ok {
+		return false
+	}
+
+	t.expires[item] = t.clock.Now().Add(d)
+	t.queue.Update(item)
+
+	return true
+}
+
+//The element left the list.
+func (t *listTTL[T]) forget(item *T) {
+	if _, ok := t.ttl[item]; !ok {
+		return
+	}
+
+	t.queue.RemoveItem(item)
+	delete(t.ttl, item)
+	delete(t.expires, item)
+}
+
+//Forgets the elements that are no longer in list.
+func (t *listTTL[T]) retain(list []*T) {
+	members := make(map[*T]bool, len(list))
+
+	for _, item := range list {
+		members[item] = true
+	}
+
+	for item := range t.ttl {
+		if !members[item] {
+			t.forget(item)
+		}
+	}
+}
+
+func (t *listTTL[T]) startJanitor(interval time.Duration) {
+	if t.stop != nil {
+		return
+	}
+
+	t.stop = make(chan struct{})
+	go runJanitor(t.clock, interval, t.stop, t.owner.purge)
+}
+
+func (t *listTTL[T]) stopJanitor() {
+	if t.stop != nil {
+		close(t.stop)
+		t.stop = nil
+	}
+}
+
+//Calls purge every interval until stop is closed.
+func runJanitor(clock Clock, interval time.Duration, stop chan struct{}, purge func()) {
+	for {
+		timer, stopTimer := clock.NewTimer(clock.Now().Add(interval))
+
+		select {
+		case <-stop:
+			stopTimer()
+			return
+		case <-timer:
+			purge()
+		}
+	}
+}
+
+/////////////////////////////////
+//           Tag TTL           //
+/////////////////////////////////
+
+//Adds a tag with the specified key and value that is removed once d passed without a Touch.
+//Returns a KeyLimit error if the tag is at its limit with LimitReject, or the error of a Unique index.
+func (l *tagList[T]) AddWithTTL(key string, value *T, d time.Duration) error {
+	if l.mapList[key] == nil {
+		l.mapList[key] = NewPointerList(l.options...)
+	}
+
+	if l.onExpire != nil {
+		l.mapList[key].OnExpire(tagExpire(key, l.onExpire))
+	}
+
+	_, err := l.mapList[key].(listInternals[T]).addLimitedWithTTL(key, value, l.limitOf(key), d)
+
+	return err
+}
+
+//Restarts the time-to-live of an element added by AddWithTTL. Returns false if it has none.
+func (l *tagList[T]) Touch(key string, value *T) bool {
+	if l.mapList[key] == nil {
+		return false
+	}
+
+	return l.mapList[key].Touch(value)
+}
+
+//Sets the func called with every expired element, after its list was released.
+func (l *tagList[T]) OnExpire(f ExpireTagFunc[T]) {
+	l.onExpire = f
+
+	for key, list := range l.mapList {
+		if list != nil {
+			list.OnExpire(tagExpire(key, f))
+		}
+	}
+}
+
+//Adds a tag with the specified key and value that is removed once d passed without a Touch.
+//Returns a KeyLimit error if the tag is at its limit with LimitReject, or the error of a Unique index.
+func (l *guardedTagList[T]) AddWithTTL(key string, value *T, d time.Duration) error {
+	l.lock()
+	defer l.unlock()
+
+	if l.mapList[key] == nil {
+		l.mapList[key] = NewGuardedPointerList(l.options...)
+	}
+
+	if l.onExpire != nil {
+		l.mapList[key].OnExpire(l.tagExpire(key, l.onExpire))
+	}
+
+	_, err := l.mapList[key].(listInternals[T]).addLimitedWithTTL(key, value, l.limitOf(key), d)
+
+	return err
+}
+
+//Restarts the time-to-live of an element added by AddWithTTL. Returns false if it has none.
+func (l *guardedTagList[T]) Touch(key string, value *T) bool {
+	l.lock()
+	defer l.unlock()
+
+	if l.mapList[key] == nil {
+		return false
+	}
+
+	return l.mapList[key].Touch(value)
+}
+
+//Sets the func called with every expired element, after the GuardedTagList was released, so it may use the GuardedTagList.
+func (l *guardedTagList[T]) OnExpire(f ExpireTagFunc[T]) {
+	l.lock()
+	defer l.unlock()
+
+	l.onExpire = f
+
+	for key, list := range l.mapList {
+		if list != nil {
+			list.OnExpire(l.tagExpire(key, f))
+		}
+	}
+}
+
+//Starts a goroutine removing the expired elements of every tag every interval, until Close.
+func (l *guardedTagList[T]) StartJanitor(interval time.Duration) {
+	l.lock()
+	defer l.unlock()
+
+	if l.stop != nil {
+		return
+	}
+
+	l.stop = make(chan struct{})
+	go runJanitor(l.clock(), interval, l.stop, l.purge)
+}
+
+//Stops the janitor.
+func (l *guardedTagList[T]) Close() error {
+	l.lock()
+	defer l.unlock()
+
+	if l.stop != nil {
+		close(l.stop)
+		l.stop = nil
+	}
+
+	return nil
+}
+
+//Clock of the lists of the tags.
+func (l *guardedTagList[T]) clock() Clock {
+	if clock := newPointerList[T](nil, nil, l.options).clock; clock != nil {
+		return clock
+	}
+
+	return SystemClock
+}
+
+//Removes the expired elements of every tag. The lists are purged after the GuardedTagList was released.
+func (l *guardedTagList[T]) purge() {
+	l.lock()
+	lists := make([]GuardedPointerList[T], 0, len(l.mapList))
+
+	for _, list := range l.mapList {
+		if list != nil {
+			lists = append(lists, list)
+		}
+	}
+
+	l.unlock()
+
+	for _, list := range lists {
+		purgeOf[T](list)
+	}
+}
+
+func tagExpire[T any](key string, f ExpireTagFunc[T]) ExpireFunc[T] {
+	return func(current *T) {
+		f(key, current)
+	}
+}
+
+//Same as tagExpire, f is called once the GuardedTagList was released.
+func (l *guardedTagList[T]) tagExpire(key string, f ExpireTagFunc[T]) ExpireFunc[T] {
+	return func(current *T) {
+		l.expiring.call(func() {
+			f(key, current)
+		})
+	}
+}
+
+//Holds back the OnExpire funcs while the lock of a tag list is taken, so that a func can use the tag list.
+//An element that expires while the lock is free, through a list returned by Get, calls its func at once.
+type expireQueue struct {
+	locker  sync.Mutex
+	held    bool
+	pending []func()
+}
+
+//The lock of the tag list was taken.
+func (q *expireQueue) hold() {
+	q.locker.Lock()
+	q.held = true
+	q.locker.Unlock()
+}
+
+//Returns the funcs held back. The lock of the tag list is released next.
+func (q *expireQueue) take() []func() {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+
+	pending := q.pending
+	q.held = false
+	q.pending = nil
+
+	return pending
+}
+
+//Calls f, or holds it back until the lock of the tag list is released.
+func (q *expireQueue) call(f func()) {
+	q.locker.Lock()
+
+	if q.held {
+		q.pending = append(q.pending, f)
+		q.locker.Unlock()
+		return
+	}
+
+	q.locker.Unlock()
+	f()
+}
+
+func callAll(funcs []func()) {
+	for _, f := range funcs {
+		f()
+	}
+}
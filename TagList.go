@@ -1,14 +1,77 @@
 package PointerList
 
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+//Access shared by TagList[T] and GuardedTagList[T], used by the package level helpers.
+type TagSource[T any] interface {
+	//Copies the elements of every tag, a cleared tag is nil.
+	ToArrayMap() map[string][]*T
+	//Removes all elements.
+	Clear()
+	//Adds a tag with the specified key and value.
+	Add(key string, value *T) (*T, error)
+}
+
+//Methods of the tag lists of this package that the package level helpers use, found by a type assertion.
+type tagInternals[T any] interface {
+	//Replaces every tag with the specified elements. A nil slice is stored as a cleared list.
+	replaceMap(items map[string][]*T)
+}
+
+//Replaces every tag with the specified elements, with Clear and Add for a TagSource[T] implemented outside the package.
+func replaceTagsOf[T any](l TagSource[T], items map[string][]*T) error {
+	if tags, ok := l.(tagInternals[T]); ok {
+		tags.replaceMap(items)
+		return nil
+	}
+
+	l.Clear()
+
+	for key, list := range items {
+		for _, item := range list {
+			if _, err := l.Add(key, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 type TagList[T any] interface {
+	TagSource[T]
+	//Encodes the list as a JSON object keyed by tag.
+	json.Marshaler
+	//Replaces the tags with a decoded JSON object.
+	json.Unmarshaler
+	//Writes a snapshot of the list using GobCodec[T].
+	io.WriterTo
+	//Replaces the tags with a snapshot written by WriteTo.
+	io.ReaderFrom
+	//Writes the list as CSV with a leading "tag" column. Without columns, the fields of T are used.
+	ExportCSV(w io.Writer, columns ...Column[T]) error
+	//Adds the rows of a CSV written by ExportCSV. Without build, the columns are parsed into the fields of T.
+	ImportCSV(r io.Reader, build CSVBuildFunc[T]) error
+	//Same as ExportCSV, separated by tabs.
+	ExportTSV(w io.Writer, columns ...Column[T]) error
+	//Same as ImportCSV, separated by tabs.
+	ImportTSV(r io.Reader, build CSVBuildFunc[T]) error
+
 	ToMap() map[string]PointerList[T]
 
 	Get(key string) PointerList[T]
 
 	GetNext(key string) *T
 
-	//Adds a tag with the specified key and value to the list.
-	Add(key string, value *T)
+	//Adds a tag with the specified key and value to the list. A tag at its limit returns a KeyLimit error
+	//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+	//that rejected the element, nothing is removed then.
+	Add(key string, value *T) (*T, error)
 
 	//Removes all elements from the TagList.
 	Clear()
@@ -19,8 +82,10 @@ type TagList[T any] interface {
 	//Determines whether a tag is in the TagList.
 	Contains(key string, value *T) bool
 
-	//Inserts an element into the TagList at the specified index.
-	Insert(index int, key string, value *T)
+	//Inserts an element into the TagList at the specified index. A tag at its limit returns a KeyLimit error
+	//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+	//that rejected the element, nothing is removed then.
+	Insert(index int, key string, value *T) (*T, error)
 
 	//Removes the first occurrence of a specific object from the TagList.
 	Remove(key string, value *T) bool
@@ -37,6 +102,15 @@ type TagList[T any] interface {
 	//Returns the number of elements in a sequence.
 	MapCount() map[string]int
 
+	//Returns the number of elements and the limit of every tag.
+	MapCountLimit() map[string]KeyCount
+
+	//Limits the number of elements of the tag, n < 1 removes the limit of the tag so the default limit applies.
+	SetKeyLimit(key string, n int, policy LimitPolicy)
+
+	//Limits the number of elements of every tag without a SetKeyLimit, n < 1 removes the limit.
+	SetDefaultLimit(n int, policy LimitPolicy)
+
 	//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]
 	MapCountSelect(f CountSelectTagListFunc[T]) map[string]int
 
@@ -48,15 +122,78 @@ type TagList[T any] interface {
 
 	//Loop
 	Foreach(f ForeachTagListFunc[T])
+
+	//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+	MapCountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (map[string]int, error)
+
+	//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+	CountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (int, error)
+
+	//Searches for an element that matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+	FindCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error)
+
+	//Loop. Returns ctx.Err() if the context is done. removeCurItem is applied once the walk completed, so a cancelled
+	//walk removes nothing.
+	ForeachCtx(ctx context.Context, f ForeachTagListFunc[T]) error
+
+	//Adds a tag with the specified key and value that is removed once d passed without a Touch.
+	AddWithTTL(key string, value *T, d time.Duration) error
+
+	//Restarts the time-to-live of an element added by AddWithTTL. Returns false if it has none.
+	Touch(key string, value *T) bool
+
+	//Sets the func called with every expired element, after its list was released.
+	OnExpire(f ExpireTagFunc[T])
+
+	//Reverts the last step of the tag, see WithHistory. Returns false if there is none or the tag has no history.
+	Undo(key string) bool
+
+	//Applies the last reverted step of the tag again. Returns false if there is none or the tag has no history.
+	Redo(key string) bool
+
+	//Determines whether there is a step of the tag to Undo.
+	CanUndo(key string) bool
+
+	//Determines whether there is a step of the tag to Redo.
+	CanRedo(key string) bool
+
+	//Names the current state of the tag for RevertTo.
+	Checkpoint(key string, name string)
+
+	//Undoes or redoes steps of the tag until it is in the state named by Checkpoint.
+	RevertTo(key string, name string) error
+
+	//Calls f and records the changes it makes to the tag as one step.
+	Batch(key string, f func())
+
+	//Returns a number increased by every change of the tag, 0 for a tag that never had a list.
+	Version(key string) uint64
+
+	//Replaces the elements of the tag if the tag is still at version, else returns ErrConflict.
+	ReplaceIfVersion(key string, version uint64, items []*T) error
+
+	//Replaces the element of the tag at the specified index if the tag is still at version, else returns ErrConflict.
+	SetIfVersion(key string, version uint64, index int, value *T) error
+
+	//Adds a tag with the specified key and value if the tag is still at version, else returns ErrConflict.
+	AddIfVersion(key string, version uint64, value *T) error
 }
 
 type tagList[T any] struct {
-	mapList map[string]PointerList[T]
+	mapList      map[string]PointerList[T]
+	options      []ListOption[T]
+	onExpire     ExpireTagFunc[T]
+	limits       map[string]keyLimit
+	defaultLimit keyLimit
+	//Versions of the dropped lists, by tag.
+	retired map[string]uint64
 }
 
-func NewTagList[T any]() TagList[T] {
+//The options are applied to the list of every tag.
+func NewTagList[T any](options ...ListOption[T]) TagList[T] {
 	return &tagList[T]{
 		mapList: make(map[string]PointerList[T]),
+		options: options,
 	}
 }
 
@@ -64,6 +201,21 @@ func (l *tagList[T]) ToMap() map[string]PointerList[T] {
 	return l.mapList
 }
 
+//Copies the elements of every tag, a cleared tag is nil.
+func (l *tagList[T]) ToArrayMap() map[string][]*T {
+	items := make(map[string][]*T, len(l.mapList))
+
+	for key, list := range l.mapList {
+		if list != nil {
+			items[key] = snapshotOf[T](list)
+		} else {
+			items[key] = nil
+		}
+	}
+
+	return items
+}
+
 func (l *tagList[T]) MapCount() map[string]int {
 	count := make(map[string]int)
 
@@ -81,8 +233,9 @@ func (l *tagList[T]) MapCount() map[string]int {
 func (l *tagList[T]) TotalCount() int {
 	count := 0
 
+	//A cleared tag has no list.
 	for _, value := range l.mapList {
-		count += value.Count()
+		count += countOf[T](value)
 	}
 
 	return count
@@ -123,6 +276,22 @@ func (l *tagList[T]) MapCountSelect(f CountSelectTagListFunc[T]) map[string]int
 	return count
 }
 
+//Replaces every tag with the specified elements.
+func (l *tagList[T]) replaceMap(items map[string][]*T) {
+	mapList := make(map[string]PointerList[T], len(items))
+
+	for key, list := range items {
+		if list != nil {
+			mapList[key] = newPointerList(list, nil, l.options)
+		} else {
+			mapList[key] = nil
+		}
+	}
+
+	l.retireAll()
+	l.mapList = mapList
+}
+
 func (l *tagList[T]) Get(key string) PointerList[T] {
 	return l.mapList[key]
 }
@@ -135,22 +304,27 @@ func (l *tagList[T]) GetNext(key string) *T {
 	return l.mapList[key].GetNext()
 }
 
-//Adds a tag with the specified key and value to the list.
-func (l *tagList[T]) Add(key string, value *T) {
+//Adds a tag with the specified key and value to the list. A tag at its limit returns a KeyLimit error
+//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+//that rejected the element, nothing is removed then.
+func (l *tagList[T]) Add(key string, value *T) (*T, error) {
 	if l.mapList[key] == nil {
-		l.mapList[key] = NewPointerList[T]()
+		l.mapList[key] = NewPointerList(l.options...)
 	}
 
-	l.mapList[key].Add(value)
+	//The tags hold lists of this package only.
+	return l.mapList[key].(listInternals[T]).insertLimited(key, value, l.limitOf(key), -1)
 }
 
 //Removes all elements from the TagList.
 func (l *tagList[T]) Clear() {
+	l.retireAll()
 	l.mapList = make(map[string]PointerList[T])
 }
 
 //Removes target list from the TagList.
 func (l *tagList[T]) ClearList(key string) {
+	l.retire(key)
 	l.mapList[key] = nil
 }
 
@@ -163,13 +337,19 @@ func (l *tagList[T]) Contains(key string, value *T) bool {
 	return l.mapList[key].Contains(value)
 }
 
-//Inserts an element into the TagList at the specified index.
-func (l *tagList[T]) Insert(index int, key string, value *T) {
+//Inserts an element into the TagList at the specified index. A tag at its limit returns a KeyLimit error
+//or the element removed to make room, by its LimitPolicy. Returns the error of a Unique index
+//that rejected the element, nothing is removed then.
+func (l *tagList[T]) Insert(index int, key string, value *T) (*T, error) {
 	if l.mapList[key] == nil {
-		l.mapList[key] = NewPointerList[T]()
+		l.mapList[key] = NewPointerList(l.options...)
 	}
 
-	l.mapList[key].Insert(value, index)
+	if index < 0 {
+		return nil, GetErrorf(IndexOutOfRange, index, l.mapList[key].Count())
+	}
+
+	return l.mapList[key].(listInternals[T]).insertLimited(key, value, l.limitOf(key), index)
 }
 
 //Removes the first occurrence of a specific object from the TagList.
@@ -219,3 +399,92 @@ func (l *tagList[T]) Foreach(f ForeachTagListFunc[T]) {
 		}
 	}
 }
+
+//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+func (l *tagList[T]) MapCountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (map[string]int, error) {
+	count := make(map[string]int)
+	visited := 0
+
+	for key, list := range l.mapList {
+		curCount := 0
+
+		if list != nil {
+			for index, current := range list.ToArray() {
+				if err := checkCtx(ctx, visited); err != nil {
+					return nil, err
+				}
+				visited++
+
+				if f(key, index, current) {
+					curCount++
+				}
+			}
+		}
+
+		count[key] = curCount
+	}
+
+	return count, nil
+}
+
+//Returns the number of elements in a sequence using the specified CountSelectTagListFunc[T]. Returns ctx.Err() if the context is done.
+func (l *tagList[T]) CountSelectCtx(ctx context.Context, f CountSelectTagListFunc[T]) (int, error) {
+	count := 0
+	visited := 0
+
+	for key, list := range l.mapList {
+		if list == nil {
+			continue
+		}
+
+		for index, current := range list.ToArray() {
+			if err := checkCtx(ctx, visited); err != nil {
+				return 0, err
+			}
+			visited++
+
+			if f(key, index, current) {
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns ctx.Err() if the context is done.
+func (l *tagList[T]) FindCtx(ctx context.Context, f FindPointerFunc[T]) (*T, error) {
+	visited := 0
+
+	for _, list := range l.mapList {
+		if list == nil {
+			continue
+		}
+
+		for index, item := range list.ToArray() {
+			if err := checkCtx(ctx, visited); err != nil {
+				return nil, err
+			}
+			visited++
+
+			if f(index, item) {
+				return item, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+//Loop. Returns ctx.Err() if the context is done. removeCurItem is applied once the walk completed, so a cancelled
+//walk removes nothing and the index passed to f is the index at the start of the walk.
+func (l *tagList[T]) ForeachCtx(ctx context.Context, f ForeachTagListFunc[T]) error {
+	removals, err := foreachTagsCtx[T](ctx, l.mapList, f)
+	if err != nil {
+		return err
+	}
+
+	applyTagRemovals(removals)
+
+	return nil
+}
@@ -22,3 +22,62 @@ type ForeachListFunc[T any] func(index int, current *T) bool
 
 //Example: return true -> next, return false -> break
 type ForeachTagListFunc[T any] func(key string, index int, current *T, removeCurItem func()) bool
+
+//Example: return &PlayerDTO{ID: current.ID}
+type MapPointerFunc[T any, U any] func(current *T) *U
+
+//Example: return acc + current.Health
+type ReducePointerFunc[T any, A any] func(acc A, current *T) A
+
+//Example: return true; => keep, return false; => skip
+type PredicatePointerFunc[T any] func(current *T) bool
+
+//Example: return current.Team
+type KeyPointerFunc[T any, K comparable] func(current *T) K
+
+//Example: left.Health < right.Health => the lowest health comes first
+type LessPointerFunc[T any] func(left *T, right *T) bool
+
+//Example: return current.Health
+type NumberPointerFunc[T any, N Number] func(current *T) N
+
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+//Example: return nil -> next, return err -> stop all workers
+type ParallelForeachListFunc[T any] func(index int, current *T) error
+
+//Example: id, err := strconv.Atoi(record["id"]); return &Player{ID: id}, err
+type CSVBuildFunc[T any] func(record map[string]string) (*T, error)
+
+//Example: return current.ID
+type IndexKeyFunc[T any] func(current *T) any
+
+//Example: return left.ID == right.ID
+type EqualPointerFunc[T any] func(left *T, right *T) bool
+
+//Example: left > right | [5,4,3] => [3,4,5]
+type SortValueFunc[T any] func(left T, right T) bool
+
+//Example: current == 0 -> true => deleted, false => skip
+type RemoveValueFunc[T any] func(current T, index int) bool
+
+//Example: return true; => finded, return false; => skip
+type FindValueFunc[T any] func(index int, current T) bool
+
+//Example: return true; => skip, return false; => direct return false;
+type TrueForAllValueFunc[T any] func(current T) bool
+
+type BeforeValueListFunc[T any] func(current T) bool
+
+//Example: return true -> next, return false -> break
+type ForeachValueListFunc[T any] func(index int, current T) bool
+
+//Example: log.Println("expired", current.ID)
+type ExpireFunc[T any] func(current *T)
+
+//Example: log.Println("expired", key, current.ID)
+type ExpireTagFunc[T any] func(key string, current *T)
@@ -0,0 +1,400 @@
+package PointerList
+
+import "time"
+
+/////////////////////////////////
+//          Value List         //
+/////////////////////////////////
+
+//List storing the values inline, without an allocation per element. Elements are compared with ==.
+type ValueList[T comparable] interface {
+	BASE
+
+	//Copies the elements.
+	ToArray() []T
+	//Returns the number of elements in a sequence.
+	Count() int
+	//Gets the element at the specified index, or the zero value.
+	Get(index int) T
+	//Gets the element at next
+	GetNext() T
+	//Gets the element at next. Returns false if no element matches.
+	GetNextBefore(f BeforeValueListFunc[T]) (T, bool)
+
+	//Adds an object to the end of the ValueList[T].
+	Add(item T)
+	//Adds the elements of the specified collection to the end of the ValueList[T].
+	AddRange(item []T)
+	//Removes the first occurrence of a specific object from the ValueList[T].
+	Remove(targetItem T) bool
+	//Removes the element at the specified index of the ValueList[T].
+	RemoveAt(index int) bool
+	//Removes all the elements that match the conditions defined by the specified predicate.
+	RemoveAll(f RemoveValueFunc[T])
+	//Removes all elements from the ValueList[T].
+	Clear()
+	//Determines whether an element is in the ValueList[T].
+	Contains(targetItem T) bool
+	//Returns the index of the first occurrence of the element, or -1.
+	IndexOf(targetItem T) int
+	//Inserts an element into the ValueList[T] at the specified index.
+	Insert(targetItem T, targetIndex int) error
+	//Inserts the elements of a collection into the ValueList[T] at the specified index.
+	InsertRange(targetItems []T, targetIndex int) error
+	//Reverses the order of the elements in the entire ValueList[T].
+	Reverse()
+	//Sorts the elements in the entire ValueList[T] using the specified SortValueFunc[T].
+	Sort(f SortValueFunc[T])
+	//Searches for an element that matches the conditions defined by the specified predicate. Returns false if no element matches.
+	Find(f FindValueFunc[T]) (T, bool)
+	//Retrieves all the elements that match the conditions defined by the specified predicate.
+	FindAll(f FindValueFunc[T]) []T
+	//Determines whether every element in the ValueList[T] matches the conditions defined by the specified predicate.
+	TrueForAll(f TrueForAllValueFunc[T]) bool
+	//Find and remove. Returns false if no element matches.
+	FindAndRemove(f FindValueFunc[T]) (T, bool)
+	//Loop
+	Foreach(f ForeachValueListFunc[T])
+}
+
+//ValueList[T] protected by mutex.
+type GuardedValueList[T comparable] interface {
+	ValueList[T]
+
+	//Adds an object to the end of the GuardedValueList[T]. Returns false if the lock could not be taken within timeout.
+	TryAdd(item T, timeout time.Duration) bool
+	//Searches for an element that matches the conditions defined by the specified predicate.
+	//Returns found == false if no element matches and locked == false if the lock could not be taken within timeout.
+	TryFind(f FindValueFunc[T], timeout time.Duration) (item T, found bool, locked bool)
+}
+
+type valueList[T comparable] struct {
+	BASE
+	list      []T
+	lastIndex int
+}
+
+func NewValueList[T comparable]() ValueList[T] {
+	return &valueList[T]{
+		list: make([]T, 0),
+	}
+}
+
+func NewGuardedValueList[T comparable]() GuardedValueList[T] {
+	baseList := &lockerBase{}
+	return &valueList[T]{
+		list: make([]T, 0),
+		BASE: baseList,
+	}
+}
+
+//Copies the elements.
+func (l *valueList[T]) ToArray() []T {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	items := make([]T, len(l.list))
+	copy(items, l.list)
+
+	return items
+}
+
+//Returns the number of elements in a sequence.
+func (l *valueList[T]) Count() int {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return len(l.list)
+}
+
+//Gets the element at the specified index, or the zero value.
+func (l *valueList[T]) Get(index int) T {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if index >= len(l.list) || index < 0 {
+		var zero T
+		return zero
+	}
+
+	return l.list[index]
+}
+
+func (l *valueList[T]) GetNext() T {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	selectedItem, _ := sliceGetNext(l.list, &l.lastIndex)
+
+	return selectedItem
+}
+
+//Gets the element at next. Returns false if no element matches.
+func (l *valueList[T]) GetNextBefore(f BeforeValueListFunc[T]) (T, bool) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	for i := 0; i < len(l.list); i++ {
+		currentItem, _ := sliceGetNext(l.list, &l.lastIndex)
+
+		if f(currentItem) {
+			return currentItem, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+//Adds an object to the end of the ValueList[T].
+func (l *valueList[T]) Add(item T) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	l.list = append(l.list, item)
+}
+
+//Adds the elements of the specified collection to the end of the ValueList[T].
+func (l *valueList[T]) AddRange(item []T) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	l.list = append(l.list, item...)
+}
+
+//Removes the first occurrence of a specific object from the ValueList[T].
+func (l *valueList[T]) Remove(targetItem T) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	var removed bool
+	l.list, removed = sliceRemoveAt(l.list, l.indexOf(targetItem))
+
+	return removed
+}
+
+//Removes the element at the specified index of the ValueList[T].
+func (l *valueList[T]) RemoveAt(index int) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	var removed bool
+	l.list, removed = sliceRemoveAt(l.list, index)
+
+	return removed
+}
+
+//Removes all the elements that match the conditions defined by the specified predicate.
+func (l *valueList[T]) RemoveAll(f RemoveValueFunc[T]) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	l.list = sliceRemoveAll(l.list, f, nil)
+}
+
+//Removes all elements from the ValueList[T].
+func (l *valueList[T]) Clear() {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	l.list = make([]T, 0)
+}
+
+//Determines whether an element is in the ValueList[T].
+func (l *valueList[T]) Contains(targetItem T) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.indexOf(targetItem) >= 0
+}
+
+//Returns the index of the first occurrence of the element, or -1.
+func (l *valueList[T]) IndexOf(targetItem T) int {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.indexOf(targetItem)
+}
+
+//Inserts an element into the ValueList[T] at the specified index.
+func (l *valueList[T]) Insert(targetItem T, targetIndex int) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	var err error
+	l.list, err = sliceInsert(l.list, targetItem, targetIndex)
+
+	return err
+}
+
+//Inserts the elements of a collection into the ValueList[T] at the specified index.
+func (l *valueList[T]) InsertRange(targetItems []T, targetIndex int) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	var err error
+	l.list, err = sliceInsertRange(l.list, targetItems, targetIndex)
+
+	return err
+}
+
+//Reverses the order of the elements in the entire ValueList[T].
+func (l *valueList[T]) Reverse() {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	sliceReverse(l.list)
+}
+
+//Sorts the elements in the entire ValueList[T] using the specified SortValueFunc[T].
+func (l *valueList[T]) Sort(f SortValueFunc[T]) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	sliceSort(l.list, f)
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate. Returns false if no element matches.
+func (l *valueList[T]) Find(f FindValueFunc[T]) (T, bool) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.find(f)
+}
+
+//Retrieves all the elements that match the conditions defined by the specified predicate.
+func (l *valueList[T]) FindAll(f FindValueFunc[T]) []T {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return sliceFindAll(l.list, f)
+}
+
+//Determines whether every element in the ValueList[T] matches the conditions defined by the specified predicate.
+func (l *valueList[T]) TrueForAll(f TrueForAllValueFunc[T]) bool {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return sliceTrueForAll(l.list, f)
+}
+
+//Find and remove. Returns false if no element matches.
+func (l *valueList[T]) FindAndRemove(f FindValueFunc[T]) (T, bool) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	index := sliceFindIndex(l.list, f)
+	if index < 0 {
+		var zero T
+		return zero, false
+	}
+
+	target := l.list[index]
+	l.list, _ = sliceRemoveAt(l.list, index)
+
+	return target, true
+}
+
+func (l *valueList[T]) Foreach(f ForeachValueListFunc[T]) {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	sliceForeach(l.list, f)
+}
+
+//Adds an object to the end of the GuardedValueList[T]. Returns false if the lock could not be taken within timeout.
+func (l *valueList[T]) TryAdd(item T, timeout time.Duration) bool {
+	if l.BASE != nil {
+		if !tryStartBase(l.BASE, timeout) {
+			return false
+		}
+		defer l.end()
+	}
+
+	l.list = append(l.list, item)
+
+	return true
+}
+
+//Searches for an element that matches the conditions defined by the specified predicate.
+//Returns found == false if no element matches and locked == false if the lock could not be taken within timeout.
+func (l *valueList[T]) TryFind(f FindValueFunc[T], timeout time.Duration) (T, bool, bool) {
+	if l.BASE != nil {
+		if !tryStartBase(l.BASE, timeout) {
+			var zero T
+			return zero, false, false
+		}
+		defer l.end()
+	}
+
+	item, found := l.find(f)
+
+	return item, found, true
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+func (l *valueList[T]) indexOf(targetItem T) int {
+	for i := 0; i < len(l.list); i++ {
+		if l.list[i] == targetItem {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (l *valueList[T]) find(f FindValueFunc[T]) (T, bool) {
+	if index := sliceFindIndex(l.list, f); index >= 0 {
+		return l.list[index], true
+	}
+
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,98 @@
+package PointerList
+
+import (
+	"testing"
+	"time"
+)
+
+type point struct {
+	X, Y int
+}
+
+func TestValueList(t *testing.T) {
+	list := NewGuardedValueList[point]()
+	list.AddRange([]point{{3, 0}, {1, 0}, {2, 0}})
+	list.Insert(point{0, 0}, 0)
+
+	list.Sort(func(left, right point) bool { return left.X > right.X })
+
+	if list.Count() != 4 || list.Get(0) != (point{0, 0}) || list.Get(3) != (point{3, 0}) || list.IndexOf(point{2, 0}) != 2 {
+		t.Errorf("Sort => %v", list.ToArray())
+	}
+
+	if !list.Remove(point{1, 0}) || list.Contains(point{1, 0}) || list.Remove(point{1, 0}) {
+		t.Errorf("Remove => %v", list.ToArray())
+	}
+
+	if item, ok := list.Find(func(index int, current point) bool { return current.X > 1 }); !ok || item.X != 2 {
+		t.Errorf("Find => %v, %v", item, ok)
+	}
+
+	if item, found, locked := list.TryFind(func(index int, current point) bool { return current.X > 5 }, time.Second); found || !locked || item != (point{}) {
+		t.Errorf("TryFind => %v, %v, %v", item, found, locked)
+	}
+
+	list.RemoveAll(func(current point, index int) bool { return current.X == 0 })
+
+	if list.GetNext() != (point{2, 0}) || list.GetNext() != (point{3, 0}) || list.GetNext() != (point{2, 0}) {
+		t.Errorf("GetNext => %v", list.ToArray())
+	}
+
+	if err := list.Insert(point{}, 5); err == nil {
+		t.Errorf("Insert(out of range) => accepted")
+	}
+}
+
+func BenchmarkAddPointerList(b *testing.B) {
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		list := NewPointerList[point]()
+
+		for i := 0; i < 1000; i++ {
+			list.Add(&point{i, i})
+		}
+	}
+}
+
+func BenchmarkAddValueList(b *testing.B) {
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		list := NewValueList[point]()
+
+		for i := 0; i < 1000; i++ {
+			list.Add(point{i, i})
+		}
+	}
+}
+
+func BenchmarkFindPointerList(b *testing.B) {
+	list := NewPointerList[point]()
+
+	for i := 0; i < 1000; i++ {
+		list.Add(&point{i, i})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		list.Find(func(index int, current *point) bool { return current.X == 999 })
+	}
+}
+
+func BenchmarkFindValueList(b *testing.B) {
+	list := NewValueList[point]()
+
+	for i := 0; i < 1000; i++ {
+		list.Add(point{i, i})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		list.Find(func(index int, current point) bool { return current.X == 999 })
+	}
+}
@@ -0,0 +1,280 @@
+package PointerList
+
+/////////////////////////////////
+//           Version           //
+/////////////////////////////////
+
+//Returned by the ...IfVersion methods when the list was changed since the version was read.
+var ErrConflict = GetError(Conflict)
+
+//Returns a number increased by every change of the list, starting at 0. Reading the version drops the expired elements
+//of AddWithTTL first, so it is the version of the list as the next method sees it.
+func (l *pointerList[T]) Version() uint64 {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	return l.version
+}
+
+//Replaces the elements with a copy of items if the list is still at version, else returns ErrConflict.
+func (l *pointerList[T]) ReplaceIfVersion(version uint64, items []*T) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.version != version {
+		return ErrConflict
+	}
+
+	l.replaceItems(cloneArray(items))
+
+	return nil
+}
+
+//Replaces the element at the specified index if the list is still at version, else returns ErrConflict.
+//Returns an IndexOutOfRange error or the error of a Unique index, the list is not changed.
+func (l *pointerList[T]) SetIfVersion(version uint64, index int, item *T) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.version != version {
+		return ErrConflict
+	} else if index < 0 || index >= len(l.list) {
+		return GetErrorf(IndexOutOfRange, index, len(l.list))
+	}
+
+	old := l.list[index]
+	l.removeAt(index)
+
+	if err := l.indexRejects(item); err != nil {
+		l.insertAt(old, index)
+		return err
+	}
+
+	l.insertAt(item, index)
+
+	return nil
+}
+
+//Adds an object to the end of the PointerList[T] if the list is still at version, else returns ErrConflict.
+//Returns the error of a Unique index that rejected the element.
+func (l *pointerList[T]) AddIfVersion(version uint64, item *T) error {
+	if l.BASE != nil {
+		l.start()
+		defer l.end()
+	}
+
+	if l.version != version {
+		return ErrConflict
+	}
+
+	if err := l.indexRejects(item); err != nil {
+		return err
+	}
+
+	l.list = append(l.list, item)
+	l.indexAdd(item, len(l.list)-1)
+
+	return nil
+}
+
+//Returns a number increased by every change of the tag, 0 for a tag that never had a list.
+//ClearList and Clear increase it as well, the tag keeps its version once its list is dropped.
+func (l *tagList[T]) Version(key string) uint64 {
+	return l.retired[key] + versionOf[T](l.mapList[key])
+}
+
+//Replaces the elements of the tag with a copy of items if the tag is still at version, else returns ErrConflict.
+//The limit of the tag is not checked.
+func (l *tagList[T]) ReplaceIfVersion(key string, version uint64, items []*T) error {
+	list, listVersion, err := l.versioned(key, version)
+	if err != nil {
+		return err
+	}
+
+	return list.ReplaceIfVersion(listVersion, items)
+}
+
+//Replaces the element of the tag at the specified index if the tag is still at version, else returns ErrConflict.
+func (l *tagList[T]) SetIfVersion(key string, version uint64, index int, value *T) error {
+	list, listVersion, err := l.versioned(key, version)
+	if err != nil {
+		return err
+	}
+
+	return list.SetIfVersion(listVersion, index, value)
+}
+
+//Adds a tag with the specified key and value if the tag is still at version, else returns ErrConflict.
+//A tag at its limit returns a KeyLimit error whatever its LimitPolicy, since making room would change the version.
+func (l *tagList[T]) AddIfVersion(key string, version uint64, value *T) error {
+	list, listVersion, err := l.versioned(key, version)
+	if err != nil {
+		return err
+	}
+
+	if err := l.limitOf(key).rejects(key, list.Count()); err != nil {
+		return err
+	}
+
+	return list.AddIfVersion(listVersion, value)
+}
+
+//Returns a number increased by every change of the tag, 0 for a tag that never had a list.
+//ClearList and Clear increase it as well, the tag keeps its version once its list is dropped.
+func (l *guardedTagList[T]) Version(key string) uint64 {
+	l.lock()
+	defer l.unlock()
+
+	return l.retired[key] + versionOf[T](l.mapList[key])
+}
+
+//Replaces the elements of the tag with a copy of items if the tag is still at version, else returns ErrConflict.
+//The limit of the tag is not checked.
+func (l *guardedTagList[T]) ReplaceIfVersion(key string, version uint64, items []*T) error {
+	l.lock()
+	defer l.unlock()
+
+	list, listVersion, err := l.versioned(key, version)
+	if err != nil {
+		return err
+	}
+
+	return list.ReplaceIfVersion(listVersion, items)
+}
+
+//Replaces the element of the tag at the specified index if the tag is still at version, else returns ErrConflict.
+func (l *guardedTagList[T]) SetIfVersion(key string, version uint64, index int, value *T) error {
+	l.lock()
+	defer l.unlock()
+
+	list, listVersion, err := l.versioned(key, version)
+	if err != nil {
+		return err
+	}
+
+	return list.SetIfVersion(listVersion, index, value)
+}
+
+//Adds a tag with the specified key and value if the tag is still at version, else returns ErrConflict.
+//A tag at its limit returns a KeyLimit error whatever its LimitPolicy, since making room would change the version.
+func (l *guardedTagList[T]) AddIfVersion(key string, version uint64, value *T) error {
+	l.lock()
+	defer l.unlock()
+
+	list, listVersion, err := l.versioned(key, version)
+	if err != nil {
+		return err
+	}
+
+	if err := l.limitOf(key).rejects(key, list.Count()); err != nil {
+		return err
+	}
+
+	return list.AddIfVersion(listVersion, value)
+}
+
+/////////////////////////////////
+//            PRIVATE          //
+/////////////////////////////////
+
+//Returns the list of the tag, created if the tag has none, and the version of the list that matches the version of the tag.
+func (l *tagList[T]) versioned(key string, version uint64) (PointerList[T], uint64, error) {
+	retired := l.retired[key]
+	if version < retired {
+		return nil, 0, ErrConflict
+	}
+
+	if l.mapList[key] == nil {
+		if version != retired {
+			return nil, 0, ErrConflict
+		}
+
+		l.mapList[key] = NewPointerList(l.options...)
+	}
+
+	return l.mapList[key], version - retired, nil
+}
+
+//Returns the list of the tag, created if the tag has none, and the version of the list that matches the version of the tag.
+//The GuardedTagList is locked.
+func (l *guardedTagList[T]) versioned(key string, version uint64) (GuardedPointerList[T], uint64, error) {
+	retired := l.retired[key]
+	if version < retired {
+		return nil, 0, ErrConflict
+	}
+
+	if l.mapList[key] == nil {
+		if version != retired {
+			return nil, 0, ErrConflict
+		}
+
+		l.mapList[key] = NewGuardedPointerList(l.options...)
+	}
+
+	return l.mapList[key], version - retired, nil
+}
+
+//Returns ErrConflict if the tag is not at version, or a KeyLimit error if one more element does not fit.
+func (l *guardedTagList[T]) checkVersion(key string, version uint64, adding bool) error {
+	l.lock()
+	defer l.unlock()
+
+	if l.retired[key]+versionOf[T](l.mapList[key]) != version {
+		return ErrConflict
+	} else if adding {
+		return l.limitOf(key).rejects(key, countOf[T](l.mapList[key]))
+	}
+
+	return nil
+}
+
+//Keeps the version of the tag increasing once its list is dropped.
+func (l *tagList[T]) retire(key string) {
+	if list := l.mapList[key]; list != nil {
+		l.retired = retireVersion(l.retired, key, list.Version())
+	}
+}
+
+func (l *tagList[T]) retireAll() {
+	for key := range l.mapList {
+		l.retire(key)
+	}
+}
+
+//Keeps the version of the tag increasing once its list is dropped. The GuardedTagList is locked.
+func (l *guardedTagList[T]) retire(key string) {
+	if list := l.mapList[key]; list != nil {
+		l.retired = retireVersion(l.retired, key, list.Version())
+	}
+}
+
+func (l *guardedTagList[T]) retireAll() {
+	for key := range l.mapList {
+		l.retire(key)
+	}
+}
+
+func retireVersion(retired map[string]uint64, key string, version uint64) map[string]uint64 {
+	if retired == nil {
+		retired = make(map[string]uint64)
+	}
+
+	retired[key] += version + 1
+
+	return retired
+}
+
+//Version of a list that may be nil.
+func versionOf[T any](list PointerList[T]) uint64 {
+	if list == nil {
+		return 0
+	}
+
+	return list.Version()
+}